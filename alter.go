@@ -0,0 +1,357 @@
+package sqlbuilder
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+type alterKind int
+
+const (
+	addColumn alterKind = iota
+	dropColumn
+	renameColumn
+	alterColumnType
+	setDefault
+	dropDefault
+	setNotNull
+	dropNotNull
+	addConstraint
+	dropConstraint
+	renameTable
+)
+
+type alterAction struct {
+	kind       alterKind
+	column     string
+	name       string
+	def        *ColumnDef
+	typ        ColumnType
+	expr       string
+	constraint *Constraint
+}
+
+// AlterTableBuilder changes an existing table. Actions are combined into a
+// single ALTER TABLE statement where the dialect allows it and split into
+// several statements otherwise, see Statements. A rename ends the combined
+// statement, so the actions keep their order.
+type AlterTableBuilder struct {
+	table   string
+	actions []alterAction
+	tags    Tags
+}
+
+var (
+	_ Statement    = (*AlterTableBuilder)(nil)
+	_ selfRenderer = (*AlterTableBuilder)(nil)
+)
+
+func AlterTable(table string) *AlterTableBuilder {
+	return &AlterTableBuilder{table: table}
+}
+
+func (b *AlterTableBuilder) add(a alterAction) *AlterTableBuilder {
+	b.actions = append(b.actions, a)
+	return b
+}
+
+func (b *AlterTableBuilder) AddColumn(column *ColumnDef) *AlterTableBuilder {
+	return b.add(alterAction{kind: addColumn, column: column.name, def: column})
+}
+
+func (b *AlterTableBuilder) DropColumn(column string) *AlterTableBuilder {
+	return b.add(alterAction{kind: dropColumn, column: column})
+}
+
+func (b *AlterTableBuilder) RenameColumn(column, to string) *AlterTableBuilder {
+	return b.add(alterAction{kind: renameColumn, column: column, name: to})
+}
+
+// AlterColumnType changes the type of column. using is an optional
+// expression converting the existing values, only supported by Postgres.
+func (b *AlterTableBuilder) AlterColumnType(column string, typ ColumnType, using ...string) *AlterTableBuilder {
+	return b.add(alterAction{kind: alterColumnType, column: column, typ: typ, expr: strings.Join(using, " ")})
+}
+
+// SetDefault sets the default of column to the SQL expression expr.
+func (b *AlterTableBuilder) SetDefault(column, expr string) *AlterTableBuilder {
+	return b.add(alterAction{kind: setDefault, column: column, expr: expr})
+}
+
+func (b *AlterTableBuilder) DropDefault(column string) *AlterTableBuilder {
+	return b.add(alterAction{kind: dropDefault, column: column})
+}
+
+func (b *AlterTableBuilder) SetNotNull(column string) *AlterTableBuilder {
+	return b.add(alterAction{kind: setNotNull, column: column})
+}
+
+func (b *AlterTableBuilder) DropNotNull(column string) *AlterTableBuilder {
+	return b.add(alterAction{kind: dropNotNull, column: column})
+}
+
+// AddConstraint adds a table constraint. Name it with Named so it can be
+// dropped later.
+func (b *AlterTableBuilder) AddConstraint(constraint *Constraint) *AlterTableBuilder {
+	return b.add(alterAction{kind: addConstraint, constraint: constraint})
+}
+
+func (b *AlterTableBuilder) DropConstraint(name string) *AlterTableBuilder {
+	return b.add(alterAction{kind: dropConstraint, name: name})
+}
+
+func (b *AlterTableBuilder) RenameTo(name string) *AlterTableBuilder {
+	return b.add(alterAction{kind: renameTable, name: name})
+}
+
+// When calls f with the builder when cond is true.
+func (b *AlterTableBuilder) When(cond bool, f func(b *AlterTableBuilder)) *AlterTableBuilder {
+	if cond {
+		f(b)
+	}
+	return b
+}
+
+func (b *AlterTableBuilder) Clone() *AlterTableBuilder {
+	c := &AlterTableBuilder{table: b.table, tags: maps.Clone(b.tags)}
+	for _, a := range b.actions {
+		if a.def != nil {
+			a.def = a.def.clone()
+		}
+		if a.constraint != nil {
+			a.constraint = a.constraint.clone()
+		}
+		c.actions = append(c.actions, a)
+	}
+	return c
+}
+
+// SQL renders the builder without validating it. An action the dialect
+// doesn't support is rendered as a comment naming it, use Build or
+// Statements to have it rejected with ErrUnsupportedByDialect.
+func (b *AlterTableBuilder) SQL() string {
+	return SQL(b)
+}
+
+func (b *AlterTableBuilder) Pretty() string {
+	return Pretty(b)
+}
+
+func (b *AlterTableBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(b, opts...)
+}
+
+func (b *AlterTableBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(b, opts...)
+}
+
+func (b *AlterTableBuilder) Tag(key, value string) Statement {
+	b.tags.set(key, value)
+	return b
+}
+
+func (b *AlterTableBuilder) GetTags() Tags {
+	return b.tags
+}
+
+func (b *AlterTableBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(b, opts...)
+}
+
+func (b *AlterTableBuilder) Err() error {
+	return Err(b)
+}
+
+func (b *AlterTableBuilder) Args() []any {
+	return nil
+}
+
+func (b *AlterTableBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(b, opts...)
+}
+
+// Statements validates the builder and returns every statement needed to
+// apply it, for drivers which can't run several statements at once.
+func (b *AlterTableBuilder) Statements(opts ...RenderOption) ([]string, error) {
+	rc := newRenderContext(opts...)
+	if err := b.validate(rc.dialect); err != nil {
+		return nil, err
+	}
+	return b.statements(rc), nil
+}
+
+func (b *AlterTableBuilder) render(rc *renderContext) {
+	sep := "; "
+	if rc.format != nil {
+		sep = ";\n"
+	}
+	rc.sb.WriteString(strings.Join(b.statements(rc), sep))
+}
+
+func (b *AlterTableBuilder) statements(rc *renderContext) []string {
+	d := rc.dialect
+	prefix := "ALTER TABLE " + b.table + " "
+
+	var stmts, combined []string
+	// flush closes the combined statement, so the actions after a rename
+	// or an EXEC run after it.
+	flush := func() {
+		if len(combined) != 0 {
+			stmts = append(stmts, prefix+strings.Join(combined, ", "))
+			combined = nil
+		}
+	}
+	for _, a := range b.actions {
+		clause, ok := a.clause(d, b.table)
+		if !ok {
+			// Only reached by SQL, Build and Statements reject the
+			// action. Leave a trace of it instead of dropping it.
+			name := alterActionNames[a.kind]
+			if a.column != "" {
+				name += " " + a.column
+			}
+			flush()
+			stmts = append(stmts, fmt.Sprintf("/* %s is not supported by %s */", name, d))
+			continue
+		}
+		switch {
+		case strings.HasPrefix(clause, "EXEC "):
+			flush()
+			stmts = append(stmts, clause)
+		case d.alterCombine && a.kind != renameColumn && a.kind != renameTable:
+			combined = append(combined, clause)
+		default:
+			flush()
+			stmts = append(stmts, prefix+clause)
+		}
+	}
+	flush()
+	return stmts
+}
+
+// clause returns the clause of the action for the dialect, or false when the
+// dialect doesn't support it.
+func (a alterAction) clause(d *Dialect, table string) (string, bool) {
+	switch a.kind {
+	case addColumn:
+		col := newRenderContext(WithDialect(d))
+		a.def.render(col)
+		switch {
+		case d == SQLServer:
+			return "ADD " + col.sb.String(), true
+		case d == MySQL && a.def.references != nil:
+			var sb strings.Builder
+			a.def.references.render(&sb)
+			return "ADD COLUMN " + col.sb.String() + ", ADD " + sb.String(), true
+		}
+		return "ADD COLUMN " + col.sb.String(), true
+	case dropColumn:
+		return "DROP COLUMN " + a.column, true
+	case renameColumn:
+		if d == SQLServer {
+			return fmt.Sprintf("EXEC sp_rename %s, %s, 'COLUMN'", d.quote(table+"."+a.column), d.quote(a.name)), true
+		}
+		return "RENAME COLUMN " + a.column + " TO " + a.name, true
+	case renameTable:
+		if d == SQLServer {
+			return fmt.Sprintf("EXEC sp_rename %s, %s", d.quote(table), d.quote(a.name)), true
+		}
+		return "RENAME TO " + a.name, true
+	case dropConstraint:
+		return "DROP CONSTRAINT " + a.name, d != SQLite
+	case addConstraint:
+		var sb strings.Builder
+		a.constraint.render(&sb)
+		return "ADD " + sb.String(), d != SQLite
+	}
+
+	switch d {
+	case Postgres:
+		switch a.kind {
+		case alterColumnType:
+			clause := "ALTER COLUMN " + a.column + " TYPE " + d.columnType(a.typ)
+			if a.expr != "" {
+				clause += " USING " + a.expr
+			}
+			return clause, true
+		case setDefault:
+			return "ALTER COLUMN " + a.column + " SET DEFAULT " + a.expr, true
+		case dropDefault:
+			return "ALTER COLUMN " + a.column + " DROP DEFAULT", true
+		case setNotNull:
+			return "ALTER COLUMN " + a.column + " SET NOT NULL", true
+		case dropNotNull:
+			return "ALTER COLUMN " + a.column + " DROP NOT NULL", true
+		}
+	case MySQL:
+		switch a.kind {
+		case alterColumnType:
+			return "MODIFY COLUMN " + a.column + " " + d.columnType(a.typ), a.expr == ""
+		case setDefault:
+			return "ALTER COLUMN " + a.column + " SET DEFAULT " + a.expr, true
+		case dropDefault:
+			return "ALTER COLUMN " + a.column + " DROP DEFAULT", true
+		}
+	case SQLServer:
+		if a.kind == alterColumnType {
+			return "ALTER COLUMN " + a.column + " " + d.columnType(a.typ), a.expr == ""
+		}
+	}
+	return "", false
+}
+
+var alterActionNames = map[alterKind]string{
+	addColumn:       "ADD COLUMN",
+	dropColumn:      "DROP COLUMN",
+	renameColumn:    "RENAME COLUMN",
+	alterColumnType: "ALTER COLUMN TYPE",
+	setDefault:      "SET DEFAULT",
+	dropDefault:     "DROP DEFAULT",
+	setNotNull:      "SET NOT NULL",
+	dropNotNull:     "DROP NOT NULL",
+	addConstraint:   "ADD CONSTRAINT",
+	dropConstraint:  "DROP CONSTRAINT",
+	renameTable:     "RENAME TO",
+}
+
+func (b *AlterTableBuilder) validate(d *Dialect) error {
+	var errs []error
+	add := func(column string, err error) {
+		errs = append(errs, &BuildError{
+			Statement: "ALTER TABLE",
+			Table:     b.table,
+			Column:    column,
+			Err:       err,
+		})
+	}
+
+	if b.table == "" {
+		add("", ErrMissingTable)
+	}
+	if len(b.actions) == 0 {
+		add("", ErrNoActions)
+	}
+	for _, a := range b.actions {
+		switch {
+		case a.kind == addColumn && a.def.typ.name == "":
+			add(a.column, ErrMissingType)
+		case a.kind == alterColumnType && a.typ.name == "":
+			add(a.column, ErrMissingType)
+		case a.kind == addConstraint:
+			if err := a.constraint.validate(); err != nil {
+				add("", err)
+			}
+		case (a.kind == renameColumn || a.kind == renameTable || a.kind == dropConstraint) && a.name == "":
+			add(a.column, fmt.Errorf("%w: %s without name", ErrInvalidAction, alterActionNames[a.kind]))
+		}
+
+		if d != nil {
+			if _, ok := a.clause(d, b.table); !ok {
+				add(a.column, fmt.Errorf("%w: %s", d.unsupported(), alterActionNames[a.kind]))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
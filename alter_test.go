@@ -0,0 +1,121 @@
+package sqlbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlterTable(t *testing.T) {
+	t.Run("case=postgres", func(t *testing.T) {
+		q := AlterTable("users").
+			AddColumn(Column("nickname", Varchar(64)).NotNull().Default("''")).
+			RenameColumn("name", "full_name").
+			AlterColumnType("score", BigInt, "score::bigint").
+			SetNotNull("email").
+			DropDefault("role").
+			AddConstraint(Unique("email").Named("users_email_key")).
+			DropConstraint("users_old_key")
+
+		s, err := q.Build()
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE users ADD COLUMN nickname VARCHAR(64) NOT NULL DEFAULT ''; "+
+			"ALTER TABLE users RENAME COLUMN name TO full_name; "+
+			"ALTER TABLE users ALTER COLUMN score TYPE BIGINT USING score::bigint, "+
+			"ALTER COLUMN email SET NOT NULL, "+
+			"ALTER COLUMN role DROP DEFAULT, "+
+			"ADD CONSTRAINT users_email_key UNIQUE (email), "+
+			"DROP CONSTRAINT users_old_key", s)
+
+		stmts, err := q.Statements()
+		require.NoError(t, err)
+		require.Len(t, stmts, 3)
+	})
+
+	t.Run("case=order", func(t *testing.T) {
+		s, err := AlterTable("users").
+			AddColumn(Column("x", Text)).
+			RenameColumn("name", "full_name").
+			SetNotNull("full_name").
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE users ADD COLUMN x TEXT; "+
+			"ALTER TABLE users RENAME COLUMN name TO full_name; "+
+			"ALTER TABLE users ALTER COLUMN full_name SET NOT NULL", s)
+	})
+
+	t.Run("case=mysql", func(t *testing.T) {
+		s, err := AlterTable("users").
+			AlterColumnType("score", BigInt).
+			SetDefault("role", "'member'").
+			DropColumn("legacy").
+			Build(WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE users MODIFY COLUMN score BIGINT, ALTER COLUMN role SET DEFAULT 'member', DROP COLUMN legacy", s)
+
+		_, err = AlterTable("users").AlterColumnType("score", BigInt, "score + 0").Build(WithDialect(MySQL))
+		require.ErrorIs(t, err, ErrUnsupportedByDialect)
+		_, err = AlterTable("users").SetNotNull("email").Build(WithDialect(MySQL))
+		require.ErrorIs(t, err, ErrUnsupportedByDialect)
+		require.Equal(t, InterpolatedPrefix+"ALTER TABLE users DROP COLUMN legacy; /* SET NOT NULL email is not supported by mysql */",
+			AlterTable("users").DropColumn("legacy").SetNotNull("email").Interpolate(WithDialect(MySQL)))
+
+		s, err = AlterTable("users").AddColumn(Column("role_id", Integer).References("roles", "id")).Build(WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE users ADD COLUMN role_id INT, ADD FOREIGN KEY (role_id) REFERENCES roles (id)", s)
+	})
+
+	t.Run("case=sqlite", func(t *testing.T) {
+		stmts, err := AlterTable("users").
+			AddColumn(Column("nickname", Text)).
+			DropColumn("legacy").
+			RenameTo("members").
+			Statements(WithDialect(SQLite))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ALTER TABLE users ADD COLUMN nickname TEXT",
+			"ALTER TABLE users DROP COLUMN legacy",
+			"ALTER TABLE users RENAME TO members",
+		}, stmts)
+
+		_, err = AlterTable("users").AddConstraint(Unique("email")).Build(WithDialect(SQLite))
+		require.ErrorIs(t, err, ErrUnsupportedByDialect)
+		_, err = AlterTable("users").AlterColumnType("score", BigInt).Build(WithDialect(SQLite))
+		require.ErrorIs(t, err, ErrUnsupportedByDialect)
+	})
+
+	t.Run("case=sqlserver", func(t *testing.T) {
+		stmts, err := AlterTable("users").
+			AddColumn(Column("nickname", Text)).
+			AlterColumnType("score", BigInt).
+			RenameColumn("name", "full_name").
+			RenameTo("members").
+			Statements(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ALTER TABLE users ADD nickname NVARCHAR(MAX)",
+			"ALTER TABLE users ALTER COLUMN score BIGINT",
+			"EXEC sp_rename N'users.name', N'full_name', 'COLUMN'",
+			"EXEC sp_rename N'users', N'members'",
+		}, stmts)
+
+		s, err := AlterTable("o'brien").RenameColumn("it's", "its").Build(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, "EXEC sp_rename N'o''brien.it''s', N'its', 'COLUMN'", s)
+	})
+
+	t.Run("case=clone", func(t *testing.T) {
+		q := AlterTable("users").DropColumn("legacy")
+		c := q.Clone().DropColumn("other")
+		require.Equal(t, "ALTER TABLE users DROP COLUMN legacy", q.SQL())
+		require.Equal(t, "ALTER TABLE users DROP COLUMN legacy, DROP COLUMN other", c.SQL())
+	})
+
+	t.Run("case=invalid", func(t *testing.T) {
+		require.ErrorIs(t, AlterTable("users").Err(), ErrNoActions)
+		require.ErrorIs(t, AlterTable("").DropColumn("id").Err(), ErrMissingTable)
+		require.ErrorIs(t, AlterTable("users").AddColumn(Column("id", ColumnType{})).Err(), ErrMissingType)
+		require.ErrorIs(t, AlterTable("users").RenameColumn("id", "").Err(), ErrInvalidAction)
+		require.ErrorIs(t, AlterTable("users").AddConstraint(ForeignKey("role_id")).Err(), ErrInvalidConstraint)
+	})
+}
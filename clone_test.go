@@ -0,0 +1,63 @@
+package sqlbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	t.Run("case=select where chain", func(t *testing.T) {
+		base := Select("id", "username").From("users").Where("active", Equals)
+
+		byID := base.Clone().And("id", Equals)
+		byName := base.Clone().And("username", Like)
+
+		require.Equal(t, "SELECT id, username FROM users WHERE active = $1", base.SQL())
+		require.Equal(t, "SELECT id, username FROM users WHERE active = $1 AND id = $2", byID.SQL())
+		require.Equal(t, "SELECT id, username FROM users WHERE active = $1 AND username LIKE $2", byName.SQL())
+	})
+
+	t.Run("case=select joins and order by", func(t *testing.T) {
+		base := Select("u.id").From("users").As("u").InnerJoin("roles").As("r").On("r.id", "u.role_id")
+
+		variant := base.Clone().LeftJoin("permissions").On("p.id", "u.permission_id").OrderBy(Desc, "u.id")
+		base.Where("u.id", Equals)
+
+		require.Equal(t, "SELECT u.id FROM users AS u INNER JOIN roles AS r ON r.id = u.role_id WHERE u.id = $1", base.SQL())
+		require.Equal(t, "SELECT u.id FROM users AS u INNER JOIN roles AS r ON r.id = u.role_id LEFT JOIN permissions ON p.id = u.permission_id ORDER BY u.id DESC", variant.SQL())
+	})
+
+	t.Run("case=insert", func(t *testing.T) {
+		base := Insert("user_id", "name").Into("users")
+		variant := base.Clone().Returning("name")
+
+		require.Equal(t, "INSERT INTO users (user_id, name) VALUES ($1, $2)", base.SQL())
+		require.Equal(t, "INSERT INTO users (user_id, name) VALUES ($1, $2) RETURNING name", variant.SQL())
+	})
+
+	t.Run("case=insert select", func(t *testing.T) {
+		base := Insert("user_id", "name").Into("users").Select("id", "name").From("accounts")
+		variant := base.Clone().Where("id", Equals)
+
+		require.Equal(t, "INSERT INTO users (user_id, name) SELECT id, name FROM accounts", base.SQL())
+		require.Equal(t, "INSERT INTO users (user_id, name) SELECT id, name FROM accounts WHERE id = $1", variant.SQL())
+	})
+
+	t.Run("case=update", func(t *testing.T) {
+		base := Update("users").Set("name")
+		variant := base.Clone().Where("id", Equals).And("tenant", Equals)
+		base.Where("email", Equals)
+
+		require.Equal(t, "UPDATE users SET name = $1 WHERE email = $2", base.SQL())
+		require.Equal(t, "UPDATE users SET name = $1 WHERE id = $2 AND tenant = $3", variant.SQL())
+	})
+
+	t.Run("case=delete", func(t *testing.T) {
+		base := Delete().From("users").Where("tenant", Equals)
+		variant := base.Clone().And("id", In(2))
+
+		require.Equal(t, "DELETE FROM users WHERE tenant = $1", base.SQL())
+		require.Equal(t, "DELETE FROM users WHERE tenant = $1 AND id IN ($2, $3)", variant.SQL())
+	})
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/Benehiko/sqlbuilder/schema"
+)
+
+type (
+	genColumn struct {
+		Ident string
+		Name  string
+		// GoType is empty for the columns left out of the model.
+		GoType string
+		Tag    string
+	}
+	genTable struct {
+		Package string
+		Name    string
+		Columns []genColumn
+		Imports []string
+		// Arrays are the array columns, left out of the model.
+		Arrays []string
+	}
+)
+
+// reserved are the identifiers generated for every table.
+var reserved = map[string]bool{"Table": true, "Columns": true, "Model": true}
+
+var initialisms = map[string]string{
+	"id": "ID", "uuid": "UUID", "url": "URL", "uri": "URI", "json": "JSON",
+	"api": "API", "http": "HTTP", "ip": "IP", "sql": "SQL", "html": "HTML",
+}
+
+var fileTemplate = template.Must(template.New("table").Parse(`// Code generated by sqlbuilder-gen. DO NOT EDIT.
+
+// Package {{.Package}} holds the identifiers of the {{.Name}} table.
+package {{.Package}}
+{{if .Imports}}
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{end}}
+// Table is the name of the {{.Name}} table.
+const Table string = {{printf "%q" .Name}}
+
+// Columns of the {{.Name}} table.
+const (
+{{- range .Columns}}
+	{{.Ident}} string = {{printf "%q" .Name}}
+{{- end}}
+)
+
+// Columns lists the columns of the {{.Name}} table in declaration order.
+var Columns = []string{
+{{- range .Columns}}
+	{{.Ident}},
+{{- end}}
+}
+
+// Model is a row of the {{.Name}} table.
+{{- if .Arrays}} Array columns are left out since
+// database/sql can't bind or scan them without a driver array type:
+// {{range $i, $c := .Arrays}}{{if $i}}, {{end}}{{$c}}{{end}}.
+{{- end}}
+type Model struct {
+{{- range .Columns}}{{if .GoType}}
+	{{.Ident}} {{.GoType}} ` + "`" + `db:{{printf "%q" .Tag}}` + "`" + `
+{{- end}}{{end}}
+}
+`))
+
+// generate returns the source of the package for t.
+func generate(t *schema.Table) ([]byte, error) {
+	g := genTable{
+		Package: packageName(t.Name),
+		Name:    t.Name,
+	}
+
+	seen := map[string]bool{}
+	imports := map[string]bool{}
+	for _, c := range t.Columns {
+		ident := goIdent(c.Name)
+		if reserved[ident] {
+			ident += "Column"
+		}
+		for base, i := ident, 2; seen[ident]; i++ {
+			ident = fmt.Sprintf("%s%d", base, i)
+		}
+		seen[ident] = true
+
+		var typ, pkg string
+		if arrayType.MatchString(c.Type) {
+			g.Arrays = append(g.Arrays, c.Name)
+		} else {
+			typ, pkg = goType(t, c)
+		}
+		if pkg != "" {
+			imports[pkg] = true
+		}
+
+		tag := c.Name
+		if t.IsPrimaryKey(c.Name) {
+			tag += ",pk"
+		}
+		if c.Identity || c.Default != "" {
+			tag += ",omitempty"
+		}
+
+		g.Columns = append(g.Columns, genColumn{
+			Ident:  ident,
+			Name:   c.Name,
+			GoType: typ,
+			Tag:    tag,
+		})
+	}
+	for pkg := range imports {
+		g.Imports = append(g.Imports, pkg)
+	}
+	slices.Sort(g.Imports)
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, g); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// packageName turns a table name into a valid package name, e.g.
+// public.user_roles becomes userroles.
+func packageName(table string) string {
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		table = table[i+1:]
+	}
+	var sb strings.Builder
+	for _, r := range strings.ToLower(table) {
+		if unicode.IsLetter(r) || (unicode.IsDigit(r) && sb.Len() > 0) {
+			sb.WriteRune(r)
+		}
+	}
+	name := sb.String()
+	if name == "" || token.IsKeyword(name) {
+		name += "table"
+	}
+	return name
+}
+
+// goIdent turns a column name into an exported identifier, e.g. user_id
+// becomes UserID.
+func goIdent(column string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(column, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if s, ok := initialisms[strings.ToLower(part)]; ok {
+			sb.WriteString(s)
+			continue
+		}
+		rs := []rune(part)
+		sb.WriteRune(unicode.ToUpper(rs[0]))
+		sb.WriteString(string(rs[1:]))
+	}
+	ident := sb.String()
+	if ident == "" || !unicode.IsLetter([]rune(ident)[0]) {
+		ident = "C" + ident
+	}
+	return ident
+}
+
+var (
+	typeName  = regexp.MustCompile(`^[a-z ]+`)
+	arrayType = regexp.MustCompile(`\s*\[\d*\]$`)
+)
+
+// goType returns the Go type of the column and the package it needs.
+// Numeric and decimal are strings to keep their precision.
+func goType(t *schema.Table, c *schema.Column) (string, string) {
+	typ := strings.ToLower(c.Type)
+	base := strings.TrimSpace(typeName.FindString(typ))
+	nullable := !c.NotNull && !t.IsPrimaryKey(c.Name)
+
+	switch base {
+	case "smallint", "int2", "smallserial", "tinyint":
+		return nullType(nullable, "int16", "sql.NullInt16")
+	case "integer", "int", "int4", "serial", "mediumint":
+		return nullType(nullable, "int32", "sql.NullInt32")
+	case "bigint", "int8", "bigserial":
+		return nullType(nullable, "int64", "sql.NullInt64")
+	case "real", "float4":
+		return nullType(nullable, "float32", "sql.Null[float32]")
+	case "double precision", "float8", "float", "double":
+		return nullType(nullable, "float64", "sql.NullFloat64")
+	case "boolean", "bool", "bit":
+		return nullType(nullable, "bool", "sql.NullBool")
+	case "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone",
+		"date", "datetime", "datetime2", "time", "timetz":
+		if nullable {
+			return "sql.NullTime", "database/sql"
+		}
+		return "time.Time", "time"
+	case "json", "jsonb":
+		return "json.RawMessage", "encoding/json"
+	case "bytea", "blob", "binary", "varbinary", "longblob":
+		return "[]byte", ""
+	}
+	return nullType(nullable, "string", "sql.NullString")
+}
+
+func nullType(nullable bool, typ, null string) (string, string) {
+	if nullable {
+		return null, "database/sql"
+	}
+	return typ, ""
+}
@@ -0,0 +1,75 @@
+// Command sqlbuilder-gen reads CREATE TABLE statements from DDL files and
+// generates a Go package per table holding its name, column identifiers and
+// a model struct with `db` tags.
+//
+//	sqlbuilder-gen -out ./internal/db ./schema/*.sql
+//
+// The identifiers are constants of type string, so they can be passed to
+// every builder but not where another string type such as sqlbuilder.Param
+// or sqlbuilder.JoinType is expected, and a renamed column becomes a compile
+// error:
+//
+//	sqlbuilder.Select(users.ID, users.Email).From(users.Table).Where(users.ID, sqlbuilder.Equals)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Benehiko/sqlbuilder/schema"
+)
+
+func main() {
+	out := flag.String("out", ".", "directory the packages are written to")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sqlbuilder-gen [-out dir] file.sql...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*out, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "sqlbuilder-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, files []string) error {
+	packages := map[string]string{}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		s, err := schema.ParseDDL(string(src))
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		for _, t := range s.Tables {
+			pkg := packageName(t.Name)
+			if other, ok := packages[pkg]; ok {
+				return fmt.Errorf("%s: tables %s and %s both map to package %s", file, other, t.Name, pkg)
+			}
+			packages[pkg] = t.Name
+
+			code, err := generate(t)
+			if err != nil {
+				return fmt.Errorf("%s: table %s: %w", file, t.Name, err)
+			}
+			dir := filepath.Join(out, pkg)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, pkg+".go"), code, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ddl = `
+CREATE TABLE users (
+	id BIGSERIAL PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	display_name TEXT,
+	settings JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	deleted_at TIMESTAMPTZ,
+	tags TEXT[] NOT NULL DEFAULT '{}',
+	scores INTEGER[],
+	balance NUMERIC(12, 2),
+	rating REAL
+);
+`
+
+const expected = `// Code generated by sqlbuilder-gen. DO NOT EDIT.
+
+// Package users holds the identifiers of the users table.
+package users
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Table is the name of the users table.
+const Table string = "users"
+
+// Columns of the users table.
+const (
+	ID          string = "id"
+	Email       string = "email"
+	DisplayName string = "display_name"
+	Settings    string = "settings"
+	CreatedAt   string = "created_at"
+	DeletedAt   string = "deleted_at"
+	Tags        string = "tags"
+	Scores      string = "scores"
+	Balance     string = "balance"
+	Rating      string = "rating"
+)
+
+// Columns lists the columns of the users table in declaration order.
+var Columns = []string{
+	ID,
+	Email,
+	DisplayName,
+	Settings,
+	CreatedAt,
+	DeletedAt,
+	Tags,
+	Scores,
+	Balance,
+	Rating,
+}
+
+// Model is a row of the users table. Array columns are left out since
+// database/sql can't bind or scan them without a driver array type:
+// tags, scores.
+type Model struct {
+	ID          int64             ` + "`db:\"id,pk,omitempty\"`" + `
+	Email       string            ` + "`db:\"email\"`" + `
+	DisplayName sql.NullString    ` + "`db:\"display_name\"`" + `
+	Settings    json.RawMessage   ` + "`db:\"settings,omitempty\"`" + `
+	CreatedAt   time.Time         ` + "`db:\"created_at,omitempty\"`" + `
+	DeletedAt   sql.NullTime      ` + "`db:\"deleted_at\"`" + `
+	Balance     sql.NullString    ` + "`db:\"balance\"`" + `
+	Rating      sql.Null[float32] ` + "`db:\"rating\"`" + `
+}
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schema.sql")
+	require.NoError(t, os.WriteFile(file, []byte(ddl), 0o644))
+
+	out := filepath.Join(dir, "out")
+	require.NoError(t, run(out, []string{file}))
+
+	code, err := os.ReadFile(filepath.Join(out, "users", "users.go"))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(code))
+}
+
+func TestNames(t *testing.T) {
+	require.Equal(t, "UserID", goIdent("user_id"))
+	require.Equal(t, "APIKeyURL", goIdent("api_key_url"))
+	require.Equal(t, "C2fa", goIdent("2fa"))
+	require.Equal(t, "userroles", packageName("public.user_roles"))
+	require.Equal(t, "selecttable", packageName("select"))
+}
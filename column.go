@@ -0,0 +1,308 @@
+package sqlbuilder
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ColumnType is a portable column type, mapped to a concrete type by each
+// dialect.
+type ColumnType struct {
+	name  string
+	size  int
+	scale int
+}
+
+var (
+	SmallInt  = ColumnType{name: "smallint"}
+	Integer   = ColumnType{name: "integer"}
+	BigInt    = ColumnType{name: "bigint"}
+	Boolean   = ColumnType{name: "boolean"}
+	Real      = ColumnType{name: "real"}
+	Double    = ColumnType{name: "double"}
+	Text      = ColumnType{name: "text"}
+	UUID      = ColumnType{name: "uuid"}
+	JSON      = ColumnType{name: "json"}
+	Bytes     = ColumnType{name: "bytes"}
+	Date      = ColumnType{name: "date"}
+	Time      = ColumnType{name: "time"}
+	Timestamp = ColumnType{name: "timestamp"}
+)
+
+// Varchar is a variable length string of at most n characters.
+func Varchar(n int) ColumnType {
+	return ColumnType{name: "varchar", size: n}
+}
+
+// Char is a fixed length string of n characters.
+func Char(n int) ColumnType {
+	return ColumnType{name: "char", size: n}
+}
+
+// Decimal is an exact number with the given precision and scale.
+func Decimal(precision, scale int) ColumnType {
+	return ColumnType{name: "decimal", size: precision, scale: scale}
+}
+
+// RawType is a type used as is for every dialect.
+func RawType(typ string) ColumnType {
+	return ColumnType{name: "raw:" + typ}
+}
+
+func (t ColumnType) String() string {
+	return Postgres.columnType(t)
+}
+
+// ReferentialAction is the action of a foreign key on delete or update.
+type ReferentialAction string
+
+const (
+	NoAction   ReferentialAction = "NO ACTION"
+	Restrict   ReferentialAction = "RESTRICT"
+	Cascade    ReferentialAction = "CASCADE"
+	SetNull    ReferentialAction = "SET NULL"
+	SetDefault ReferentialAction = "SET DEFAULT"
+)
+
+// ColumnDef defines a column of a table.
+type ColumnDef struct {
+	name       string
+	typ        ColumnType
+	notNull    bool
+	def        string
+	identity   bool
+	primaryKey bool
+	unique     bool
+	references *Constraint
+	check      string
+}
+
+// Column defines a column of the given type. Columns are nullable unless
+// NotNull is set.
+func Column(name string, typ ColumnType) *ColumnDef {
+	return &ColumnDef{
+		name: name,
+		typ:  typ,
+	}
+}
+
+func (c *ColumnDef) NotNull() *ColumnDef {
+	c.notNull = true
+	return c
+}
+
+// Default sets the default of the column to the SQL expression expr, which
+// is rendered as is, e.g. Default("now()") or Default("'active'").
+func (c *ColumnDef) Default(expr string) *ColumnDef {
+	c.def = expr
+	return c
+}
+
+// Identity makes the column auto incrementing.
+func (c *ColumnDef) Identity() *ColumnDef {
+	c.identity = true
+	return c
+}
+
+func (c *ColumnDef) PrimaryKey() *ColumnDef {
+	c.primaryKey = true
+	return c
+}
+
+func (c *ColumnDef) Unique() *ColumnDef {
+	c.unique = true
+	return c
+}
+
+// References adds a foreign key from the column to column of table.
+func (c *ColumnDef) References(table, column string, onDelete ...ReferentialAction) *ColumnDef {
+	c.references = ForeignKey(c.name).References(table, column)
+	if len(onDelete) > 0 {
+		c.references.OnDelete(onDelete[0])
+	}
+	return c
+}
+
+// Check adds a CHECK constraint with the SQL expression expr.
+func (c *ColumnDef) Check(expr string) *ColumnDef {
+	c.check = expr
+	return c
+}
+
+func (c *ColumnDef) clone() *ColumnDef {
+	n := *c
+	if c.references != nil {
+		n.references = c.references.clone()
+	}
+	return &n
+}
+
+func (c *ColumnDef) render(rc *renderContext) {
+	sb := &rc.sb
+	d := rc.dialect
+
+	sb.WriteString(c.name)
+	sb.WriteString(" ")
+	if c.identity && d == SQLite {
+		// SQLite only auto increments INTEGER PRIMARY KEY columns.
+		sb.WriteString("INTEGER PRIMARY KEY AUTOINCREMENT")
+	} else {
+		sb.WriteString(d.columnType(c.typ))
+		if c.identity {
+			sb.WriteString(" ")
+			sb.WriteString(d.identity)
+		}
+	}
+	if c.notNull {
+		sb.WriteString(" NOT NULL")
+	}
+	if c.def != "" {
+		sb.WriteString(" DEFAULT ")
+		sb.WriteString(c.def)
+	}
+	if c.primaryKey && !(c.identity && d == SQLite) {
+		sb.WriteString(" PRIMARY KEY")
+	}
+	if c.unique {
+		sb.WriteString(" UNIQUE")
+	}
+	if c.references != nil && d != MySQL {
+		// MySQL ignores REFERENCES on a column, the foreign key is a
+		// table constraint instead, see CreateTableBuilder.render.
+		sb.WriteString(" ")
+		c.references.renderReferences(sb)
+	}
+	if c.check != "" {
+		sb.WriteString(" CHECK (")
+		sb.WriteString(c.check)
+		sb.WriteString(")")
+	}
+}
+
+type constraintKind string
+
+const (
+	primaryKeyConstraint constraintKind = "PRIMARY KEY"
+	uniqueConstraint     constraintKind = "UNIQUE"
+	foreignKeyConstraint constraintKind = "FOREIGN KEY"
+	checkConstraint      constraintKind = "CHECK"
+)
+
+// Constraint is a table constraint.
+type Constraint struct {
+	name       string
+	kind       constraintKind
+	columns    []string
+	refTable   string
+	refColumns []string
+	onDelete   ReferentialAction
+	onUpdate   ReferentialAction
+	check      string
+}
+
+func PrimaryKey(columns ...string) *Constraint {
+	return &Constraint{kind: primaryKeyConstraint, columns: columns}
+}
+
+func Unique(columns ...string) *Constraint {
+	return &Constraint{kind: uniqueConstraint, columns: columns}
+}
+
+// ForeignKey references another table from columns, see References.
+func ForeignKey(columns ...string) *Constraint {
+	return &Constraint{kind: foreignKeyConstraint, columns: columns}
+}
+
+// Check is a CHECK constraint with the SQL expression expr.
+func Check(expr string) *Constraint {
+	return &Constraint{kind: checkConstraint, check: expr}
+}
+
+// Named names the constraint.
+func (c *Constraint) Named(name string) *Constraint {
+	c.name = name
+	return c
+}
+
+func (c *Constraint) References(table string, columns ...string) *Constraint {
+	c.refTable = table
+	c.refColumns = columns
+	return c
+}
+
+func (c *Constraint) OnDelete(action ReferentialAction) *Constraint {
+	c.onDelete = action
+	return c
+}
+
+func (c *Constraint) OnUpdate(action ReferentialAction) *Constraint {
+	c.onUpdate = action
+	return c
+}
+
+func (c *Constraint) clone() *Constraint {
+	n := *c
+	n.columns = slices.Clone(c.columns)
+	n.refColumns = slices.Clone(c.refColumns)
+	return &n
+}
+
+func (c *Constraint) render(sb *strings.Builder) {
+	if c.name != "" {
+		sb.WriteString("CONSTRAINT ")
+		sb.WriteString(c.name)
+		sb.WriteString(" ")
+	}
+	sb.WriteString(string(c.kind))
+	if c.kind == checkConstraint {
+		sb.WriteString(" (")
+		sb.WriteString(c.check)
+		sb.WriteString(")")
+		return
+	}
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(c.columns, ", "))
+	sb.WriteString(")")
+	if c.kind == foreignKeyConstraint {
+		sb.WriteString(" ")
+		c.renderReferences(sb)
+	}
+}
+
+func (c *Constraint) renderReferences(sb *strings.Builder) {
+	sb.WriteString("REFERENCES ")
+	sb.WriteString(c.refTable)
+	if len(c.refColumns) != 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(c.refColumns, ", "))
+		sb.WriteString(")")
+	}
+	if c.onDelete != "" {
+		sb.WriteString(" ON DELETE ")
+		sb.WriteString(string(c.onDelete))
+	}
+	if c.onUpdate != "" {
+		sb.WriteString(" ON UPDATE ")
+		sb.WriteString(string(c.onUpdate))
+	}
+}
+
+// columnTypes maps portable types to the types of a dialect. %d is replaced
+// by the size of the type, decimals take a precision and a scale.
+type columnTypes map[string]string
+
+func (types columnTypes) columnType(t ColumnType) string {
+	if raw, ok := strings.CutPrefix(t.name, "raw:"); ok {
+		return raw
+	}
+	typ := types[t.name]
+	switch t.name {
+	case "varchar", "char":
+		typ = strings.Replace(typ, "%d", strconv.Itoa(t.size), 1)
+	case "decimal":
+		typ = strings.Replace(typ, "%d", strconv.Itoa(t.size), 1)
+		typ = strings.Replace(typ, "%d", strconv.Itoa(t.scale), 1)
+	}
+	return typ
+}
@@ -1,8 +1,19 @@
 package sqlbuilder
 
+import (
+	"maps"
+	"slices"
+)
+
 type (
 	DeleteFromQuery interface {
-		SQL() string
+		Statement
+		Clone() DeleteFromQuery
+		// HardDelete deletes the rows even from a table of a
+		// SoftDeleteScope.
+		HardDelete() DeleteFromQuery
+		// When calls f with the delete when cond is true.
+		When(cond bool, f func(q DeleteFromQuery)) DeleteFromQuery
 		Where[DeleteFromQuery]
 	}
 	DeleteQuery interface {
@@ -14,7 +25,8 @@ type (
 		joins   []*Join
 		columns []string
 		orderBy *Sort
-		pos     int
+		hard    bool
+		tags    Tags
 		*WhereBuilder[DeleteFromQuery]
 	}
 )
@@ -35,6 +47,11 @@ func (d *DeleteBuilder) From(table string) DeleteFromQuery {
 	return d
 }
 
+func (d *DeleteBuilder) HardDelete() DeleteFromQuery {
+	d.hard = true
+	return d
+}
+
 func (d *DeleteBuilder) Where(column string, operator Operator) WhereOptions[DeleteFromQuery] {
 	d.WhereBuilder = &WhereBuilder[DeleteFromQuery]{
 		parent: d,
@@ -43,9 +60,91 @@ func (d *DeleteBuilder) Where(column string, operator Operator) WhereOptions[Del
 			Op:      operator,
 		},
 	}
+	return d.WhereBuilder
+}
+
+func (d *DeleteBuilder) WhereOptional(column string, operator Operator, value any) WhereOptions[DeleteFromQuery] {
+	d.WhereBuilder = &WhereBuilder[DeleteFromQuery]{
+		parent: d,
+		where:  optional(column, operator, value),
+	}
+	return d.WhereBuilder
+}
+
+func (d *DeleteBuilder) When(cond bool, f func(q DeleteFromQuery)) DeleteFromQuery {
+	if cond {
+		f(d)
+	}
 	return d
 }
 
+func (d *DeleteBuilder) SQL() string {
+	return SQL(d)
+}
+
+func (d *DeleteBuilder) Pretty() string {
+	return Pretty(d)
+}
+
+func (d *DeleteBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(d, opts...)
+}
+
+func (d *DeleteBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(d, opts...)
+}
+
+func (d *DeleteBuilder) Tag(key, value string) Statement {
+	d.tags.set(key, value)
+	return d
+}
+
+func (d *DeleteBuilder) GetTags() Tags {
+	return d.tags
+}
+
+func (d *DeleteBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(d, opts...)
+}
+
+func (d *DeleteBuilder) Err() error {
+	return Err(d)
+}
+
+func (d *DeleteBuilder) Args() []any {
+	return Args(d)
+}
+
+func (d *DeleteBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(d, opts...)
+}
+
+// Clone returns a deep copy of the delete, including its where chain.
+func (d *DeleteBuilder) Clone() DeleteFromQuery {
+	return d.clone()
+}
+
+func (d *DeleteBuilder) clone() *DeleteBuilder {
+	c := &DeleteBuilder{
+		table:   d.table,
+		alias:   d.alias,
+		columns: slices.Clone(d.columns),
+		hard:    d.hard,
+		tags:    maps.Clone(d.tags),
+	}
+	if d.WhereBuilder != nil {
+		c.WhereBuilder = &WhereBuilder[DeleteFromQuery]{
+			parent: c,
+			where:  d.where.Clone(),
+		}
+	}
+	return c
+}
+
+func (d *DeleteBuilder) cloneWhere() *WhereBuilder[DeleteFromQuery] {
+	return d.clone().WhereBuilder
+}
+
 // d.L implements DeleteWhereOptionsQuery
 func (d *DeleteBuilder) Table() string {
 	return d.table
@@ -73,12 +172,7 @@ func (d *DeleteBuilder) GetOrderBy() *Sort {
 
 // GetParent implementd.queryHelper
 func (d *DeleteBuilder) GetParent() any {
-	return d.parent
-}
-
-// GetPod.tion implements queryHelper
-func (d *DeleteBuilder) GetPosition() *int {
-	return &d.pos
+	return nil
 }
 
 // GetReturning implementd.queryHelper
@@ -86,6 +180,11 @@ func (d *DeleteBuilder) GetReturning() []string {
 	return nil
 }
 
+// GetPosition implements queryHelper
+func (d *DeleteBuilder) GetPosition() *int {
+	return new(int)
+}
+
 // GetTable implementd.queryHelper
 func (d *DeleteBuilder) GetTable() string {
 	return d.table
@@ -93,5 +192,8 @@ func (d *DeleteBuilder) GetTable() string {
 
 // GetWhere implementd.queryHelper
 func (d *DeleteBuilder) GetWhere() *WhereCondition {
-	return d.where
+	if d.WhereBuilder != nil {
+		return d.where
+	}
+	return nil
 }
@@ -0,0 +1,166 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Dialect describes the differences between the databases a statement can
+// be rendered for.
+type Dialect struct {
+	name        string
+	placeholder func(pos int) string
+	returning   bool
+	types       columnTypes
+	identity    string
+	ifNotExists bool
+	cascade     bool
+	// alterCombine is set when several ALTER TABLE actions can be
+	// combined into one statement.
+	alterCombine bool
+	// now is the expression evaluating to the current timestamp.
+	now string
+	// reuse is set when a placeholder can be repeated to bind the same
+	// value again, see Param.
+	reuse bool
+	// named returns the placeholder of a named parameter bound with
+	// sql.Named, if the dialect has them.
+	named func(name string) string
+}
+
+var (
+	Postgres = &Dialect{
+		name: "postgres",
+		now:  "now()",
+		placeholder: func(pos int) string {
+			return "$" + strconv.Itoa(pos)
+		},
+		returning: true,
+		reuse:     true,
+		types: columnTypes{
+			"smallint":  "SMALLINT",
+			"integer":   "INTEGER",
+			"bigint":    "BIGINT",
+			"boolean":   "BOOLEAN",
+			"real":      "REAL",
+			"double":    "DOUBLE PRECISION",
+			"decimal":   "NUMERIC(%d, %d)",
+			"text":      "TEXT",
+			"varchar":   "VARCHAR(%d)",
+			"char":      "CHAR(%d)",
+			"uuid":      "UUID",
+			"json":      "JSONB",
+			"bytes":     "BYTEA",
+			"date":      "DATE",
+			"time":      "TIME",
+			"timestamp": "TIMESTAMPTZ",
+		},
+		identity:     "GENERATED BY DEFAULT AS IDENTITY",
+		ifNotExists:  true,
+		cascade:      true,
+		alterCombine: true,
+	}
+	MySQL = &Dialect{
+		name: "mysql",
+		now:  "NOW(6)",
+		placeholder: func(int) string {
+			return "?"
+		},
+		types: columnTypes{
+			"smallint":  "SMALLINT",
+			"integer":   "INT",
+			"bigint":    "BIGINT",
+			"boolean":   "BOOLEAN",
+			"real":      "FLOAT",
+			"double":    "DOUBLE",
+			"decimal":   "DECIMAL(%d, %d)",
+			"text":      "TEXT",
+			"varchar":   "VARCHAR(%d)",
+			"char":      "CHAR(%d)",
+			"uuid":      "CHAR(36)",
+			"json":      "JSON",
+			"bytes":     "BLOB",
+			"date":      "DATE",
+			"time":      "TIME",
+			"timestamp": "DATETIME(6)",
+		},
+		identity:     "AUTO_INCREMENT",
+		ifNotExists:  true,
+		alterCombine: true,
+	}
+	SQLite = &Dialect{
+		name: "sqlite",
+		now:  "CURRENT_TIMESTAMP",
+		placeholder: func(int) string {
+			return "?"
+		},
+		returning: true,
+		types: columnTypes{
+			"smallint":  "INTEGER",
+			"integer":   "INTEGER",
+			"bigint":    "INTEGER",
+			"boolean":   "BOOLEAN",
+			"real":      "REAL",
+			"double":    "REAL",
+			"decimal":   "NUMERIC(%d, %d)",
+			"text":      "TEXT",
+			"varchar":   "VARCHAR(%d)",
+			"char":      "CHAR(%d)",
+			"uuid":      "TEXT",
+			"json":      "TEXT",
+			"bytes":     "BLOB",
+			"date":      "DATE",
+			"time":      "TIME",
+			"timestamp": "DATETIME",
+		},
+		identity:    "AUTOINCREMENT",
+		ifNotExists: true,
+	}
+	SQLServer = &Dialect{
+		name: "sqlserver",
+		now:  "SYSDATETIMEOFFSET()",
+		placeholder: func(pos int) string {
+			return "@p" + strconv.Itoa(pos)
+		},
+		named: func(name string) string {
+			return "@" + name
+		},
+		types: columnTypes{
+			"smallint":  "SMALLINT",
+			"integer":   "INT",
+			"bigint":    "BIGINT",
+			"boolean":   "BIT",
+			"real":      "REAL",
+			"double":    "FLOAT",
+			"decimal":   "DECIMAL(%d, %d)",
+			"text":      "NVARCHAR(MAX)",
+			"varchar":   "NVARCHAR(%d)",
+			"char":      "NCHAR(%d)",
+			"uuid":      "UNIQUEIDENTIFIER",
+			"json":      "NVARCHAR(MAX)",
+			"bytes":     "VARBINARY(MAX)",
+			"date":      "DATE",
+			"time":      "TIME",
+			"timestamp": "DATETIMEOFFSET",
+		},
+		identity: "IDENTITY(1,1)",
+	}
+)
+
+func (d *Dialect) String() string {
+	return d.name
+}
+
+// SupportsReturning reports whether the dialect understands RETURNING.
+func (d *Dialect) SupportsReturning() bool {
+	return d.returning
+}
+
+// columnType returns the type t is rendered as in the dialect.
+func (d *Dialect) columnType(t ColumnType) string {
+	return d.types.columnType(t)
+}
+
+func (d *Dialect) unsupported() error {
+	return fmt.Errorf("%w %s", ErrUnsupportedByDialect, d)
+}
@@ -0,0 +1,148 @@
+package sqlbuilder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnsupportedStatement = errors.New("unsupported statement")
+	ErrMissingTable         = errors.New("missing table")
+	ErrEmptySet             = errors.New("no columns to SET")
+	ErrEmptyIn              = errors.New("IN operator without values")
+	ErrJoinWithoutCondition = errors.New("join without ON condition")
+	ErrReturningUnsupported = errors.New("RETURNING is not supported by the dialect")
+	ErrValueCountMismatch   = errors.New("value count does not match column count")
+	ErrInvalidStruct        = errors.New("value is not a struct or a slice of structs")
+	ErrNoColumns            = errors.New("no columns")
+	ErrMissingType          = errors.New("column without type")
+	ErrMultiplePrimaryKeys  = errors.New("multiple primary keys")
+	ErrInvalidConstraint    = errors.New("invalid constraint")
+	ErrUnsupportedByDialect = errors.New("not supported by the dialect")
+	ErrNoActions            = errors.New("no actions")
+	ErrInvalidAction        = errors.New("invalid action")
+	ErrMissingValue         = errors.New("condition without value")
+	ErrMissingTenant        = errors.New("no tenant in context")
+	ErrUnsupportedSyntax    = errors.New("unsupported syntax")
+	ErrUnknownOperator      = errors.New("unknown operator")
+	ErrUnsupportedVersion   = errors.New("unsupported version")
+	ErrInvalidDocument      = errors.New("invalid document")
+	ErrMissingParam         = errors.New("no value for parameter")
+	ErrUnsupportedOption    = errors.New("unsupported render option")
+)
+
+// BuildError describes a single problem found while validating a statement.
+// Use errors.Is with one of the Err* values to inspect its cause.
+type BuildError struct {
+	// Statement is the kind of statement, e.g. SELECT.
+	Statement string
+	// Table is the table the problem relates to, if any.
+	Table string
+	// Column is the column the problem relates to, if any.
+	Column string
+	Err    error
+}
+
+func (e *BuildError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("sqlbuilder: ")
+	sb.WriteString(e.Statement)
+	if e.Table != "" {
+		sb.WriteString(" " + e.Table)
+	}
+	if e.Column != "" {
+		sb.WriteString(" (" + e.Column + ")")
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// validate returns every problem found in the statement joined into a single
+// error. Dialect specific checks are skipped when d is nil.
+func validate(q queryHelper, d *Dialect) error {
+	var errs []error
+	add := func(stmt, table, column string, err error) {
+		errs = append(errs, &BuildError{
+			Statement: stmt,
+			Table:     table,
+			Column:    column,
+			Err:       err,
+		})
+	}
+
+	var stmt string
+	switch any(q).(type) {
+	case InsertQuery:
+		stmt = "INSERT"
+		ib, ok := q.(*InsertBuilder)
+		if !ok {
+			break
+		}
+		if ib.err != nil {
+			add(stmt, ib.table, "", ib.err)
+		}
+		for _, row := range ib.values {
+			if len(row) != 0 && len(row) != len(ib.columns) {
+				add(stmt, ib.table, "", fmt.Errorf("%w: %d values for %d columns", ErrValueCountMismatch, len(row), len(ib.columns)))
+			}
+		}
+		if s, ok := ib.s.(*SelectBuilder); ok && len(s.columns) != 0 && len(s.columns) != len(ib.columns) {
+			add(stmt, ib.table, "", fmt.Errorf("%w: %d selected columns for %d columns", ErrValueCountMismatch, len(s.columns), len(ib.columns)))
+		}
+	case SelectQuery:
+		stmt = "SELECT"
+		if sb, ok := q.(*SelectBuilder); ok && sb.err != nil {
+			add(stmt, sb.table, "", sb.err)
+		}
+		if p, ok := q.GetParent().(queryHelper); ok {
+			if err := validate(p, d); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	case UpdateQuery:
+		stmt = "UPDATE"
+		if ub, ok := q.(*UpdateBuilder); ok && ub.err != nil {
+			add(stmt, ub.table, "", ub.err)
+		}
+		if len(q.GetColumns()) == 0 {
+			add(stmt, q.GetTable(), "", ErrEmptySet)
+		}
+	case DeleteQuery:
+		stmt = "DELETE"
+	default:
+		return ErrUnsupportedStatement
+	}
+
+	if q.GetTable() == "" {
+		add(stmt, "", "", ErrMissingTable)
+	}
+
+	for _, j := range q.GetJoins() {
+		if j.table == "" {
+			add(string(j.join), "", "", ErrMissingTable)
+		}
+		if j.on == nil {
+			add(string(j.join), j.table, "", ErrJoinWithoutCondition)
+		}
+	}
+
+	q.GetWhere().walk(func(c *WhereCondition) {
+		if op, ok := c.Op.get().(SpecialOperator); ok {
+			if count, _ := op(); count <= 0 {
+				add(stmt, q.GetTable(), c.ColumnA, ErrEmptyIn)
+			}
+		}
+	})
+
+	if d != nil && len(q.GetReturning()) != 0 && !d.SupportsReturning() {
+		add(stmt, q.GetTable(), "", fmt.Errorf("%w %s", ErrReturningUnsupported, d))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package sqlbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	t.Run("case=valid statement", func(t *testing.T) {
+		s, err := Select("id").From("users").Where("id", In(2)).Build()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT id FROM users WHERE id IN ($1, $2)", s)
+	})
+
+	t.Run("case=dialect placeholders", func(t *testing.T) {
+		q := Update("users").Set("name").Where("id", Equals)
+
+		s, err := q.Build(WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET name = ? WHERE id = ?", s)
+
+		s, err = q.Build(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET name = @p1 WHERE id = @p2", s)
+	})
+
+	t.Run("case=empty in", func(t *testing.T) {
+		q := Select("id").From("users").Where("id", In(0)).And("name", Equals)
+
+		_, err := q.Build()
+		require.ErrorIs(t, err, ErrEmptyIn)
+
+		var be *BuildError
+		require.ErrorAs(t, err, &be)
+		require.Equal(t, "id", be.Column)
+		require.Equal(t, "SELECT", be.Statement)
+
+		require.Equal(t, "SELECT id FROM users WHERE id IN () AND name = $1", q.SQL())
+	})
+
+	t.Run("case=empty set", func(t *testing.T) {
+		_, err := Update("users").Set().Build()
+		require.ErrorIs(t, err, ErrEmptySet)
+	})
+
+	t.Run("case=missing table", func(t *testing.T) {
+		require.ErrorIs(t, Select().From("").Err(), ErrMissingTable)
+		require.ErrorIs(t, Delete().From("").Err(), ErrMissingTable)
+		require.ErrorIs(t, Insert("id").Into("").Err(), ErrMissingTable)
+	})
+
+	t.Run("case=join without condition", func(t *testing.T) {
+		q := Select("id").From("users").As("u")
+		q.InnerJoin("roles")
+
+		require.NotPanics(t, func() { q.SQL() })
+		require.ErrorIs(t, q.Err(), ErrJoinWithoutCondition)
+
+		q.(*SelectBuilder).GetJoins()[0].Filter("org_id", Equals, 7)
+		require.ErrorIs(t, q.Err(), ErrJoinWithoutCondition)
+	})
+
+	t.Run("case=returning unsupported", func(t *testing.T) {
+		q := Insert("id").Into("users").Returning("id")
+
+		require.NoError(t, q.Err())
+
+		_, err := q.Build(WithDialect(MySQL))
+		require.ErrorIs(t, err, ErrReturningUnsupported)
+
+		s, err := q.Build(WithDialect(SQLite))
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO users (id) VALUES (?) RETURNING id", s)
+	})
+
+	t.Run("case=value count mismatch", func(t *testing.T) {
+		_, err := Insert("id", "name").Into("users").Values(1).Build()
+		require.ErrorIs(t, err, ErrValueCountMismatch)
+
+		_, err = Insert("id", "name").Into("users").Select("id").From("accounts").Build()
+		require.ErrorIs(t, err, ErrValueCountMismatch)
+	})
+
+	t.Run("case=errors are accumulated", func(t *testing.T) {
+		err := Update("").Set().Where("id", NotIn(0)).Err()
+
+		require.ErrorIs(t, err, ErrMissingTable)
+		require.ErrorIs(t, err, ErrEmptySet)
+		require.ErrorIs(t, err, ErrEmptyIn)
+		require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+	})
+}
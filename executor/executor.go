@@ -0,0 +1,241 @@
+// Package executor runs sqlbuilder statements on a database/sql connection
+// and scans their results into Go values.
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/Benehiko/sqlbuilder"
+	"github.com/Benehiko/sqlbuilder/internal/fields"
+)
+
+type (
+	// Querier is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+	Querier interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	}
+	// Statement is implemented by every sqlbuilder statement.
+	Statement interface {
+		Build(opts ...sqlbuilder.RenderOption) (string, error)
+		Args() []any
+	}
+	Executor struct {
+		q    Querier
+		opts []sqlbuilder.RenderOption
+	}
+)
+
+var (
+	_ Querier = (*sql.DB)(nil)
+	_ Querier = (*sql.Tx)(nil)
+	_ Querier = (*sql.Conn)(nil)
+
+	_ Statement = sqlbuilder.Statement(nil)
+)
+
+var (
+	ErrInvalidDestination = errors.New("executor: destination must be a non-nil pointer")
+	ErrUnknownColumn      = errors.New("executor: no destination for column")
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// New returns an Executor running statements on q. The options are used to
+// build every statement, e.g. sqlbuilder.WithDialect.
+func New(q Querier, opts ...sqlbuilder.RenderOption) *Executor {
+	return &Executor{
+		q:    q,
+		opts: opts,
+	}
+}
+
+// build renders the statement with the tags carried by ctx, see
+// sqlbuilder.ContextWithTags, and returns its values in placeholder order,
+// with args bound to the placeholders without a value.
+func (e *Executor) build(ctx context.Context, stmt Statement, args []any) (string, []any, error) {
+	var bound []any
+	opts := append(slices.Clone(e.opts), sqlbuilder.WithContext(ctx), sqlbuilder.WithArgs(args...), sqlbuilder.CaptureArgs(&bound))
+	query, err := stmt.Build(opts...)
+	if err != nil {
+		return "", nil, err
+	}
+	if bound == nil {
+		// The statement may not support CaptureArgs.
+		bound = append(stmt.Args(), args...)
+	}
+	return query, bound, nil
+}
+
+// Exec runs the statement with args bound to its placeholders without a
+// bound value.
+func (e *Executor) Exec(ctx context.Context, stmt Statement, args ...any) (sql.Result, error) {
+	query, args, err := e.build(ctx, stmt, args)
+	if err != nil {
+		return nil, err
+	}
+	return e.q.ExecContext(ctx, query, args...)
+}
+
+// Get runs the statement and scans the first row into dest, which must be
+// a pointer to a struct, a map[string]any or a scalar. It returns
+// sql.ErrNoRows when the statement returned no rows.
+func (e *Executor) Get(ctx context.Context, dest any, stmt Statement, args ...any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ErrInvalidDestination
+	}
+
+	rows, err := e.query(ctx, stmt, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := scanRow(rows, v.Elem()); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// Select runs the statement and appends every row to dest, which must be a
+// pointer to a slice of structs, pointers to structs, maps or scalars.
+func (e *Executor) Select(ctx context.Context, dest any, stmt Statement, args ...any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w to a slice", ErrInvalidDestination)
+	}
+
+	rows, err := e.query(ctx, stmt, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scanAll(rows, v.Elem())
+}
+
+// All runs the statement and returns every row scanned into a T.
+func All[T any](ctx context.Context, e *Executor, stmt Statement, args ...any) ([]T, error) {
+	var dest []T
+	if err := e.Select(ctx, &dest, stmt, args...); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// One runs the statement and returns the first row scanned into a T.
+func One[T any](ctx context.Context, e *Executor, stmt Statement, args ...any) (T, error) {
+	var dest T
+	err := e.Get(ctx, &dest, stmt, args...)
+	return dest, err
+}
+
+func (e *Executor) query(ctx context.Context, stmt Statement, args []any) (*sql.Rows, error) {
+	query, args, err := e.build(ctx, stmt, args)
+	if err != nil {
+		return nil, err
+	}
+	return e.q.QueryContext(ctx, query, args...)
+}
+
+func scanAll(rows *sql.Rows, slice reflect.Value) error {
+	et := slice.Type().Elem()
+	for rows.Next() {
+		elem := reflect.New(et).Elem()
+		if err := scanRow(rows, elem); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// scanRow scans the current row into v, which must be addressable.
+func scanRow(rows *sql.Rows, v reflect.Value) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	if v.Kind() == reflect.Pointer && !isScalar(v.Type()) {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case isScalar(v.Type()):
+		if len(columns) != 1 {
+			return fmt.Errorf("executor: scanning %d columns into %s", len(columns), v.Type())
+		}
+		return rows.Scan(v.Addr().Interface())
+
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		values := make([]any, len(columns))
+		for i := range values {
+			values[i] = reflect.New(v.Type().Elem()).Interface()
+		}
+		if err := rows.Scan(values...); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(columns)))
+		}
+		for i, c := range columns {
+			v.SetMapIndex(reflect.ValueOf(c).Convert(v.Type().Key()), reflect.ValueOf(values[i]).Elem())
+		}
+		return nil
+
+	case v.Kind() == reflect.Struct:
+		byColumn := make(map[string]fields.Field)
+		for _, f := range fields.Of(v.Type()) {
+			byColumn[f.Column] = f
+		}
+		targets := make([]any, len(columns))
+		for i, c := range columns {
+			f, ok := byColumn[c]
+			if !ok {
+				return fmt.Errorf("%w %q in %s", ErrUnknownColumn, c, v.Type())
+			}
+			targets[i] = f.Addr(v)
+		}
+		return rows.Scan(targets...)
+	}
+
+	return fmt.Errorf("%w: unsupported type %s", ErrInvalidDestination, v.Type())
+}
+
+// isScalar reports whether values of t are scanned from a single column.
+func isScalar(t reflect.Type) bool {
+	if t == timeType || reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return false
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Pointer:
+		return isScalar(t.Elem())
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Benehiko/sqlbuilder"
+	"github.com/Benehiko/sqlbuilder/internal/fakedb"
+)
+
+type (
+	audit struct {
+		CreatedAt time.Time `db:"created_at"`
+	}
+	Profile struct {
+		Bio sql.NullString `db:"bio"`
+	}
+	user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+		audit
+		*Profile
+	}
+)
+
+func TestExecutor(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	rows := fakedb.Result{
+		Columns: []string{"id", "name", "created_at", "bio"},
+		Rows: [][]driver.Value{
+			{int64(1), "foo", now, "hello"},
+			{int64(2), "bar", now, nil},
+		},
+	}
+	db, fake := fakedb.Open(func(string, []any) fakedb.Result { return rows })
+	e := New(db)
+
+	t.Run("case=select into structs", func(t *testing.T) {
+		var users []user
+		q := sqlbuilder.Select("id", "name", "created_at", "bio").From("users").Where("id", sqlbuilder.In(2))
+		require.NoError(t, e.Select(ctx, &users, q, 1, 2))
+
+		require.Len(t, users, 2)
+		require.Equal(t, int64(1), users[0].ID)
+		require.Equal(t, "foo", users[0].Name)
+		require.Equal(t, now, users[0].CreatedAt)
+		require.Equal(t, sql.NullString{String: "hello", Valid: true}, users[0].Bio)
+		require.False(t, users[1].Bio.Valid)
+
+		calls := fake.Calls()
+		require.Equal(t, "SELECT id, name, created_at, bio FROM users WHERE id IN ($1, $2)", calls[len(calls)-1].Query)
+		require.Equal(t, []any{int64(1), int64(2)}, calls[len(calls)-1].Args)
+	})
+
+	t.Run("case=select into struct pointers", func(t *testing.T) {
+		var users []*user
+		require.NoError(t, e.Select(ctx, &users, sqlbuilder.Select().From("users")))
+		require.Len(t, users, 2)
+		require.Equal(t, "bar", users[1].Name)
+	})
+
+	t.Run("case=get struct", func(t *testing.T) {
+		var u user
+		require.NoError(t, e.Get(ctx, &u, sqlbuilder.Select().From("users")))
+		require.Equal(t, "foo", u.Name)
+	})
+
+	t.Run("case=get map", func(t *testing.T) {
+		var m map[string]any
+		require.NoError(t, e.Get(ctx, &m, sqlbuilder.Select().From("users")))
+		require.Equal(t, "foo", m["name"])
+		require.Equal(t, int64(1), m["id"])
+	})
+
+	t.Run("case=unknown column", func(t *testing.T) {
+		var v struct {
+			ID int64 `db:"id"`
+		}
+		require.ErrorIs(t, e.Get(ctx, &v, sqlbuilder.Select().From("users")), ErrUnknownColumn)
+	})
+
+	t.Run("case=invalid destination", func(t *testing.T) {
+		var users []user
+		require.ErrorIs(t, e.Select(ctx, users, sqlbuilder.Select().From("users")), ErrInvalidDestination)
+	})
+
+	t.Run("case=invalid statement", func(t *testing.T) {
+		var users []user
+		require.ErrorIs(t, e.Select(ctx, &users, sqlbuilder.Select().From("")), sqlbuilder.ErrMissingTable)
+	})
+}
+
+func TestExecutorScalars(t *testing.T) {
+	ctx := context.Background()
+
+	db, _ := fakedb.Open(func(string, []any) fakedb.Result {
+		return fakedb.Result{
+			Columns: []string{"id"},
+			Rows:    [][]driver.Value{{int64(1)}, {int64(2)}},
+		}
+	})
+	e := New(db)
+
+	var ids []int64
+	require.NoError(t, e.Select(ctx, &ids, sqlbuilder.Select("id").From("users")))
+	require.Equal(t, []int64{1, 2}, ids)
+
+	var id sql.NullInt64
+	require.NoError(t, e.Get(ctx, &id, sqlbuilder.Select("id").From("users")))
+	require.Equal(t, sql.NullInt64{Int64: 1, Valid: true}, id)
+}
+
+func TestExecutorExec(t *testing.T) {
+	ctx := context.Background()
+
+	db, fake := fakedb.Open(func(string, []any) fakedb.Result {
+		return fakedb.Result{RowsAffected: 1}
+	})
+	e := New(db, sqlbuilder.WithDialect(sqlbuilder.MySQL))
+
+	t.Run("case=bound values", func(t *testing.T) {
+		res, err := e.Exec(ctx, sqlbuilder.InsertStruct("users", user{ID: 1, Name: "foo"}))
+		require.NoError(t, err)
+
+		n, err := res.RowsAffected()
+		require.NoError(t, err)
+		require.EqualValues(t, 1, n)
+
+		calls := fake.Calls()
+		require.Equal(t, "INSERT INTO users (id, name, created_at, bio) VALUES (?, ?, ?, ?)", calls[len(calls)-1].Query)
+	})
+
+	t.Run("case=extra args follow bound values", func(t *testing.T) {
+		_, err := e.Exec(ctx, sqlbuilder.Update("users").Set("name").Where("id", sqlbuilder.Equals), "foo", 1)
+		require.NoError(t, err)
+
+		calls := fake.Calls()
+		require.Equal(t, []any{"foo", int64(1)}, calls[len(calls)-1].Args)
+	})
+
+	t.Run("case=missing args before bound values", func(t *testing.T) {
+		q := sqlbuilder.Update("users").Set("name").Where("org_id", sqlbuilder.Equals).AndOptional("id", sqlbuilder.Equals, 1).Parent().(sqlbuilder.Statement)
+		_, err := e.Exec(ctx, q, "foo")
+		require.ErrorIs(t, err, sqlbuilder.ErrMissingValue)
+	})
+
+	t.Run("case=no rows", func(t *testing.T) {
+		var u user
+		require.ErrorIs(t, e.Get(ctx, &u, sqlbuilder.Select().From("users")), sql.ErrNoRows)
+	})
+
+	t.Run("case=hooks", func(t *testing.T) {
+		hooks := &sqlbuilder.Hooks{}
+		hooks.Register(func(s *sqlbuilder.Structure) error {
+			s.Filter("org_id", sqlbuilder.Equals, 7)
+			return nil
+		})
+		e := New(db, sqlbuilder.WithDialect(sqlbuilder.MySQL), sqlbuilder.WithHooks(hooks))
+		_, err := e.Exec(ctx, sqlbuilder.Delete().From("users").Where("id", sqlbuilder.Equals), 1)
+		require.NoError(t, err)
+
+		calls := fake.Calls()
+		require.Equal(t, "DELETE FROM users WHERE org_id = ? AND id = ?", calls[len(calls)-1].Query)
+		require.Equal(t, []any{int64(7), int64(1)}, calls[len(calls)-1].Args)
+	})
+
+	t.Run("case=context tags", func(t *testing.T) {
+		ctx := sqlbuilder.ContextWithTags(ctx, sqlbuilder.Tags{"request_id": "abc"})
+		_, err := e.Exec(ctx, sqlbuilder.Delete().From("users").Tag("route", "/users"))
+		require.NoError(t, err)
+
+		calls := fake.Calls()
+		require.Equal(t, "DELETE FROM users /*request_id='abc',route='%2Fusers'*/", calls[len(calls)-1].Query)
+	})
+}
+
+func TestTyped(t *testing.T) {
+	ctx := context.Background()
+
+	db, fake := fakedb.Open(func(string, []any) fakedb.Result {
+		return fakedb.Result{
+			Columns: []string{"id", "name"},
+			Rows:    [][]driver.Value{{int64(1), "foo"}, {int64(2), "bar"}},
+		}
+	})
+	e := New(db)
+
+	type account struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	accounts, err := All[account](ctx, e, sqlbuilder.SelectFor[account]().From("accounts").As("a"))
+	require.NoError(t, err)
+	require.Equal(t, []account{{1, "foo"}, {2, "bar"}}, accounts)
+	require.Equal(t, []string{"SELECT a.id, a.name FROM accounts AS a"}, fake.Queries())
+
+	a, err := One[account](ctx, e, sqlbuilder.SelectFor[account]().From("accounts"))
+	require.NoError(t, err)
+	require.Equal(t, account{1, "foo"}, a)
+}
@@ -0,0 +1,270 @@
+// Package filter turns the filters of list APIs into conditions and sort
+// terms of a sqlbuilder select. Filters come either as query parameters,
+//
+//	?status=eq:active&created_at=gte:2024-01-01&sort=-created_at
+//
+// or as a JSON document nesting and/or:
+//
+//	{
+//	  "or": [
+//	    {"field": "status", "op": "eq", "value": "active"},
+//	    {"and": [
+//	      {"field": "status", "op": "eq", "value": "pending"},
+//	      {"field": "created_at", "op": "gte", "value": "2024-01-01"}
+//	    ]}
+//	  ],
+//	  "sort": ["-created_at"]
+//	}
+//
+// An Allowlist maps the field names of the API to columns and the operators
+// permitted on them, and applies a filter to a select. Values are always
+// bound, never written into the statement.
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Benehiko/sqlbuilder"
+)
+
+type (
+	// Operator is the name of a comparison in a filter.
+	Operator string
+	// Node is a condition of a filter: either a comparison of Field with
+	// Value, or And or Or of further nodes.
+	Node struct {
+		And   []Node   `json:"and,omitempty"`
+		Or    []Node   `json:"or,omitempty"`
+		Field string   `json:"field,omitempty"`
+		Op    Operator `json:"op,omitempty"`
+		// Value is compared with the field. In and NotIn take a list of
+		// values, Null takes true for IS NULL and false for IS NOT NULL.
+		Value any `json:"value,omitempty"`
+	}
+	// Filter is a parsed filter. Sort holds field names, descending when
+	// prefixed with "-".
+	Filter struct {
+		Node
+		Sort []string `json:"sort,omitempty"`
+	}
+	// Field is a field of an API which can be filtered or sorted.
+	Field struct {
+		Column string
+		// Operators are the operators permitted on the field. A field
+		// without operators can't be filtered.
+		Operators []Operator
+		Sortable  bool
+	}
+	// Allowlist maps the field names of an API to their Field. Filters on
+	// other fields are rejected.
+	Allowlist map[string]Field
+)
+
+const (
+	Eq    Operator = "eq"
+	Ne    Operator = "ne"
+	Gt    Operator = "gt"
+	Gte   Operator = "gte"
+	Lt    Operator = "lt"
+	Lte   Operator = "lte"
+	Like  Operator = "like"
+	In    Operator = "in"
+	NotIn Operator = "nin"
+	Null  Operator = "null"
+)
+
+// SortKey is the query parameter holding the sort terms.
+const SortKey = "sort"
+
+var (
+	ErrInvalidFilter      = errors.New("filter: invalid filter")
+	ErrUnknownField       = errors.New("filter: unknown field")
+	ErrUnknownOperator    = errors.New("filter: unknown operator")
+	ErrOperatorNotAllowed = errors.New("filter: operator not allowed")
+	ErrNotSortable        = errors.New("filter: field not sortable")
+)
+
+var operators = map[Operator]sqlbuilder.BasicOperator{
+	Eq:   sqlbuilder.Equals,
+	Ne:   sqlbuilder.NotEqual,
+	Gt:   sqlbuilder.GreaterThan,
+	Gte:  sqlbuilder.GreaterThanOrEqual,
+	Lt:   sqlbuilder.LessThan,
+	Lte:  sqlbuilder.LessThanOrEqual,
+	Like: sqlbuilder.Like,
+}
+
+func (o Operator) known() bool {
+	_, ok := operators[o]
+	return ok || o == In || o == NotIn || o == Null
+}
+
+// ParseQuery parses query parameters of the form field=op:value, e.g.
+// status=eq:active, which all have to hold. A value without a known
+// operator, e.g. status=active, is compared with eq, and in and nin take a
+// comma separated list. The sort parameter holds a comma separated list of
+// fields. Parameters named in ignore, e.g. page, are skipped.
+func ParseQuery(values url.Values, ignore ...string) (*Filter, error) {
+	f := &Filter{}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		switch {
+		case slices.Contains(ignore, key):
+			continue
+		case key == SortKey:
+			for _, v := range values[key] {
+				f.Sort = append(f.Sort, strings.Split(v, ",")...)
+			}
+			continue
+		}
+		for _, v := range values[key] {
+			n := Node{Field: key, Op: Eq, Value: v}
+			if op, value, ok := strings.Cut(v, ":"); ok && Operator(op).known() {
+				n.Op, n.Value = Operator(op), value
+				switch n.Op {
+				case In, NotIn:
+					n.Value = strings.Split(value, ",")
+				case Null:
+					if value != "true" && value != "false" {
+						return nil, fmt.Errorf("%w: %s=%s", ErrInvalidFilter, key, v)
+					}
+					n.Value = value == "true"
+				}
+			}
+			f.And = append(f.And, n)
+		}
+	}
+	return f, nil
+}
+
+// ParseJSON parses a filter document. Unknown keys are rejected.
+func ParseJSON(data []byte) (*Filter, error) {
+	f := &Filter{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(f); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	return f, nil
+}
+
+// Apply adds the conditions of f to the where chain of q and its sort terms
+// after those of q. It fails when f uses a field or operator the allowlist
+// doesn't permit.
+func (a Allowlist) Apply(q sqlbuilder.SelectFromQuery, f *Filter) (sqlbuilder.SelectFromQuery, error) {
+	cond, err := a.condition(f.Node)
+	if err != nil {
+		return nil, err
+	}
+	type term struct {
+		order  sqlbuilder.OrderBy
+		column string
+	}
+	terms := make([]term, 0, len(f.Sort))
+	for _, s := range f.Sort {
+		t := term{order: sqlbuilder.Asc}
+		switch {
+		case strings.HasPrefix(s, "-"):
+			t.order, s = sqlbuilder.Desc, s[1:]
+		case strings.HasPrefix(s, "+"):
+			s = s[1:]
+		}
+		field, ok := a[s]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, s)
+		case !field.Sortable:
+			return nil, fmt.Errorf("%w: %s", ErrNotSortable, s)
+		}
+		t.column = field.Column
+		terms = append(terms, t)
+	}
+
+	if cond != nil {
+		q = q.Filter(cond)
+	}
+	for _, t := range terms {
+		q = q.ThenBy(t.order, t.column)
+	}
+	return q, nil
+}
+
+// condition returns the condition of the node, or nil for an empty node.
+func (a Allowlist) condition(n Node) (*sqlbuilder.WhereCondition, error) {
+	switch {
+	case n.Field == "" && n.And == nil && n.Or == nil:
+		if n.Op != "" || n.Value != nil {
+			return nil, fmt.Errorf("%w: condition without field", ErrInvalidFilter)
+		}
+		return nil, nil
+	case n.Field != "" && (n.And != nil || n.Or != nil), n.And != nil && n.Or != nil:
+		return nil, fmt.Errorf("%w: node has to be either a comparison, and or or", ErrInvalidFilter)
+	case n.And != nil:
+		return a.conditions(n.And, sqlbuilder.AllOf)
+	case n.Or != nil:
+		return a.conditions(n.Or, sqlbuilder.AnyOf)
+	}
+
+	field, ok := a[n.Field]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownField, n.Field)
+	case !n.Op.known():
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOperator, n.Op)
+	case !slices.Contains(field.Operators, n.Op):
+		return nil, fmt.Errorf("%w: %s on %s", ErrOperatorNotAllowed, n.Op, n.Field)
+	}
+
+	switch n.Op {
+	case In, NotIn:
+		values, ok := n.Value.([]any)
+		if s, isStrings := n.Value.([]string); isStrings {
+			values, ok = make([]any, len(s)), true
+			for i, v := range s {
+				values[i] = v
+			}
+		}
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("%w: %s %s takes a non-empty list", ErrInvalidFilter, n.Field, n.Op)
+		}
+		op := sqlbuilder.In
+		if n.Op == NotIn {
+			op = sqlbuilder.NotIn
+		}
+		return sqlbuilder.Cond(field.Column, op(len(values)), values...), nil
+	case Null:
+		null, ok := n.Value.(bool)
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: %s null takes true or false", ErrInvalidFilter, n.Field)
+		case null:
+			return sqlbuilder.Cond(field.Column, sqlbuilder.IsNull), nil
+		}
+		return sqlbuilder.Cond(field.Column, sqlbuilder.IsNotNull), nil
+	}
+	switch n.Value.(type) {
+	case nil, []any, map[string]any:
+		return nil, fmt.Errorf("%w: %s %s takes a single value", ErrInvalidFilter, n.Field, n.Op)
+	}
+	return sqlbuilder.Cond(field.Column, operators[n.Op], n.Value), nil
+}
+
+func (a Allowlist) conditions(nodes []Node, combine func(...*sqlbuilder.WhereCondition) *sqlbuilder.WhereCondition) (*sqlbuilder.WhereCondition, error) {
+	conds := make([]*sqlbuilder.WhereCondition, 0, len(nodes))
+	for _, n := range nodes {
+		c, err := a.condition(n)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			conds = append(conds, c)
+		}
+	}
+	return combine(conds...), nil
+}
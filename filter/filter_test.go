@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Benehiko/sqlbuilder"
+)
+
+var allow = Allowlist{
+	"status":     {Column: "u.status", Operators: []Operator{Eq, Ne, In}},
+	"created_at": {Column: "u.created_at", Operators: []Operator{Gte, Lt}, Sortable: true},
+	"name":       {Column: "u.name", Operators: []Operator{Like, Null}, Sortable: true},
+	"id":         {Column: "u.id"},
+}
+
+func TestParseQuery(t *testing.T) {
+	t.Run("case=apply", func(t *testing.T) {
+		values, err := url.ParseQuery("status=eq:active&created_at=gte:2024-01-01&created_at=lt:2025-01-01&sort=-created_at,name&page=2")
+		require.NoError(t, err)
+		f, err := ParseQuery(values, "page")
+		require.NoError(t, err)
+
+		q, err := allow.Apply(sqlbuilder.Select().From("users").As("u").Where("u.org_id", sqlbuilder.Equals).Or("u.public", sqlbuilder.IsTrue).Parent(), f)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM users AS u WHERE u.created_at >= $1 AND u.created_at < $2 AND u.status = $3 "+
+			"AND (u.org_id = $4 OR u.public IS TRUE) ORDER BY u.created_at DESC, u.name ASC", q.SQL())
+
+		var args []any
+		_, err = q.Build(sqlbuilder.WithArgs(7), sqlbuilder.CaptureArgs(&args))
+		require.NoError(t, err)
+		require.Equal(t, []any{"2024-01-01", "2025-01-01", "active", 7}, args)
+	})
+
+	t.Run("case=operators", func(t *testing.T) {
+		for query, expected := range map[string]string{
+			"status=active":       "SELECT * FROM users WHERE u.status = $1",
+			"status=in:a,b":       "SELECT * FROM users WHERE u.status IN ($1, $2)",
+			"name=null:true":      "SELECT * FROM users WHERE u.name IS NULL",
+			"name=null:false":     "SELECT * FROM users WHERE u.name IS NOT NULL",
+			"status=ne:x:y":       "SELECT * FROM users WHERE u.status != $1",
+			"sort=%2Bname":        "SELECT * FROM users ORDER BY u.name ASC",
+			"name=like:a%25&id=1": "",
+		} {
+			values, err := url.ParseQuery(query)
+			require.NoError(t, err)
+			f, err := ParseQuery(values)
+			require.NoError(t, err)
+			q, err := allow.Apply(sqlbuilder.Select().From("users"), f)
+			if expected == "" {
+				require.ErrorIs(t, err, ErrOperatorNotAllowed, query)
+				continue
+			}
+			require.NoError(t, err, query)
+			require.Equal(t, expected, q.SQL(), query)
+		}
+
+		_, err := ParseQuery(url.Values{"name": {"null:yes"}})
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	})
+
+	t.Run("case=rejected", func(t *testing.T) {
+		for query, expected := range map[string]error{
+			"password=eq:x":        ErrUnknownField,
+			"status=gte:a":         ErrOperatorNotAllowed,
+			"sort=status":          ErrNotSortable,
+			"sort=-password":       ErrUnknownField,
+			"name=like:a&page=1":   ErrUnknownField,
+			"created_at=eq:2024-1": ErrOperatorNotAllowed,
+		} {
+			values, err := url.ParseQuery(query)
+			require.NoError(t, err)
+			f, err := ParseQuery(values)
+			require.NoError(t, err)
+			_, err = allow.Apply(sqlbuilder.Select().From("users"), f)
+			require.ErrorIs(t, err, expected, query)
+		}
+	})
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Run("case=apply", func(t *testing.T) {
+		f, err := ParseJSON([]byte(`{
+			"or": [
+				{"field": "status", "op": "in", "value": ["active", "invited"]},
+				{"and": [
+					{"field": "status", "op": "eq", "value": "pending"},
+					{"field": "created_at", "op": "gte", "value": "2024-01-01"}
+				]}
+			],
+			"sort": ["-created_at"]
+		}`))
+		require.NoError(t, err)
+
+		q, err := allow.Apply(sqlbuilder.Select().From("users").As("u").Where("u.org_id", sqlbuilder.Equals).Parent(), f)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM users AS u WHERE (u.status IN ($1, $2) OR (u.status = $3 AND u.created_at >= $4)) "+
+			"AND u.org_id = $5 ORDER BY u.created_at DESC", q.SQL())
+		require.Equal(t, []any{"active", "invited", "pending", "2024-01-01"}, q.Args())
+
+		f, err = ParseJSON([]byte(`{"and": [{"field": "name", "op": "null", "value": false}]}`))
+		require.NoError(t, err)
+		q, err = allow.Apply(sqlbuilder.Select().From("users"), f)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM users WHERE u.name IS NOT NULL", q.SQL())
+
+		f, err = ParseJSON([]byte(`{}`))
+		require.NoError(t, err)
+		q, err = allow.Apply(sqlbuilder.Select().From("users"), f)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM users", q.SQL())
+	})
+
+	t.Run("case=rejected", func(t *testing.T) {
+		for doc, expected := range map[string]error{
+			`{"field": "status", "op": "between", "value": 1}`:                 ErrUnknownOperator,
+			`{"field": "status", "op": "in", "value": []}`:                     ErrInvalidFilter,
+			`{"field": "status", "op": "eq", "value": ["a"]}`:                  ErrInvalidFilter,
+			`{"field": "status", "op": "eq", "value": "a", "or": []}`:          ErrInvalidFilter,
+			`{"and": [{"field": "secret", "op": "eq", "value": "a"}]}`:         ErrUnknownField,
+			`{"or": [{"field": "name", "op": "null", "value": "yes"}]}`:        ErrInvalidFilter,
+			`{"and": [{"field": "status", "op": "eq", "value": "a"}], "x": 1}`: ErrInvalidFilter,
+		} {
+			f, err := ParseJSON([]byte(doc))
+			if err == nil {
+				_, err = allow.Apply(sqlbuilder.Select().From("users"), f)
+			}
+			require.ErrorIs(t, err, expected, doc)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Fingerprint identifies the shape of a statement, e.g. to group metrics by
+// query. Statements which only differ in their values, the length of their
+// IN lists or the number of rows they insert have the same fingerprint.
+type Fingerprint struct {
+	// Normalized is the statement with every value written as ?, a single
+	// value in each IN list and a single row of VALUES.
+	Normalized string
+	// Hash is the 64-bit FNV-1a hash of Normalized.
+	Hash uint64
+}
+
+// String returns the hash as 16 hexadecimal digits.
+func (f Fingerprint) String() string {
+	return fmt.Sprintf("%016x", f.Hash)
+}
+
+// FingerprintOf renders the normalized form of the statement and hashes it.
+// It is rendered from the builder, so the values and dialect of the
+// statement never affect the fingerprint. Its tags do, unless OmitTags is
+// given.
+func FingerprintOf[T any](q T, opts ...RenderOption) Fingerprint {
+	rc := newRenderContext(opts...)
+	rc.normalize = true
+	renderStatement(rc.prepare(q), rc)
+
+	h := fnv.New64a()
+	h.Write([]byte(rc.sb.String()))
+	return Fingerprint{Normalized: rc.sb.String(), Hash: h.Sum64()}
+}
@@ -0,0 +1,50 @@
+package sqlbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Run("case=same shape", func(t *testing.T) {
+		a := Select("id").From("users").Where("id", In(3)).And("name", Equals).Fingerprint()
+		b := Select("id").From("users").Where("id", In(40)).And("name", Equals).Fingerprint()
+		require.Equal(t, "SELECT id FROM users WHERE id IN (?) AND name = ?", a.Normalized)
+		require.Equal(t, a, b)
+		require.Len(t, a.String(), 16)
+
+		i := Insert("id", "name").Into("users").Values(1, "a").Values(2, "b").Returning("id").Fingerprint()
+		require.Equal(t, "INSERT INTO users (id, name) VALUES (?, ?) RETURNING id", i.Normalized)
+		require.Equal(t, i, Insert("id", "name").Into("users").Values(3, "c").Returning("id").Fingerprint())
+
+		p, err := ParseSelect("SELECT id FROM users WHERE name = 'a' AND id IN (1, 2, 3)")
+		require.NoError(t, err)
+		q, err := ParseSelect("SELECT id FROM users WHERE name = $1 AND id IN ($2, $3)")
+		require.NoError(t, err)
+		require.Equal(t, p.Fingerprint(), q.Fingerprint())
+
+		require.Equal(t,
+			CreateIndex("i").On("users", "email").Where("role", Equals, "admin").Fingerprint(),
+			CreateIndex("i").On("users", "email").Where("role", Equals, "user").Fingerprint())
+	})
+
+	t.Run("case=different shape", func(t *testing.T) {
+		a := Select("id").From("users").Where("id", In(3)).Fingerprint()
+		for _, q := range []Statement{
+			Select("id").From("users").Where("id", NotIn(3)),
+			Select("id").From("users").Where("id", Equals),
+			Select("id", "name").From("users").Where("id", In(3)),
+			Select("id").From("accounts").Where("id", In(3)),
+			Delete().From("users").Where("id", In(3)),
+		} {
+			require.NotEqual(t, a.Hash, q.Fingerprint().Hash, q.SQL())
+		}
+	})
+
+	t.Run("case=where chain", func(t *testing.T) {
+		q := Update("users").Set("name").Where("id", Equals)
+		require.Equal(t, "UPDATE users SET name = ? WHERE id = ?", q.Fingerprint().Normalized)
+		require.Equal(t, q.Fingerprint(), FingerprintOf(q.Parent()))
+	})
+}
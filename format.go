@@ -0,0 +1,101 @@
+package sqlbuilder
+
+import "strings"
+
+// Format configures how Pretty lays out a statement. Every clause starts on
+// its own line, joins are indented with their ON conditions aligned, and the
+// conditions of a WHERE clause are aligned below each other.
+type Format struct {
+	// Indent is written before joins and broken list items. An empty
+	// Indent uses two spaces.
+	Indent string
+	// Lowercase writes the keywords of SELECT, INSERT, UPDATE and DELETE
+	// statements in lower case.
+	Lowercase bool
+	// Width is the line length after which a column list is broken into
+	// one column per line. Zero never breaks lists.
+	Width int
+}
+
+// DefaultFormat is the format used by Pretty unless WithFormat is given.
+var DefaultFormat = Format{Indent: "  ", Width: 80}
+
+// WithFormat renders the statement laid out in the given format instead of
+// on a single line.
+func WithFormat(f Format) RenderOption {
+	return func(rc *renderContext) {
+		rc.format = &f
+	}
+}
+
+// Pretty renders the statement without validating it, laid out in the
+// DefaultFormat. The statement is the same one SQL renders, only the
+// whitespace and keyword casing differ.
+func Pretty[T any](q T, opts ...RenderOption) string {
+	rc := newRenderContext(append([]RenderOption{WithFormat(DefaultFormat)}, opts...)...)
+	renderStatement(rc.prepare(q), rc)
+	return rc.sb.String()
+}
+
+// indent returns the indentation of the format, if any.
+func (rc *renderContext) indent() string {
+	switch {
+	case rc.format == nil:
+		return ""
+	case rc.format.Indent == "":
+		return "  "
+	}
+	return rc.format.Indent
+}
+
+// keyword writes the keyword in the casing of the format.
+func (rc *renderContext) keyword(s string) {
+	if rc.format != nil && rc.format.Lowercase {
+		s = strings.ToLower(s)
+	}
+	rc.sb.WriteString(s)
+}
+
+// clause starts the clause with the given keyword, on a new line when
+// formatting.
+func (rc *renderContext) clause(kw string) {
+	rc.newline("", " ")
+	rc.keyword(kw)
+}
+
+// newline starts a new line followed by indent when formatting and writes
+// sep otherwise.
+func (rc *renderContext) newline(indent, sep string) {
+	if rc.format == nil {
+		rc.sb.WriteString(sep)
+		return
+	}
+	rc.sb.WriteString("\n")
+	rc.sb.WriteString(indent)
+}
+
+// list writes the items separated by commas after sep. When formatting and
+// the items do not fit on the line, every item is written on its own
+// indented line instead and list reports true.
+func (rc *renderContext) list(sep string, items []string) bool {
+	s := strings.TrimSpace(strings.Join(items, ", "))
+	if f := rc.format; f == nil || f.Width == 0 || len(items) < 2 || rc.column()+len(sep)+len(s) <= f.Width {
+		rc.sb.WriteString(sep)
+		rc.sb.WriteString(s)
+		return false
+	}
+	for i, item := range items {
+		if i > 0 {
+			rc.sb.WriteString(",")
+		}
+		rc.newline(rc.indent(), "")
+		rc.sb.WriteString(strings.TrimSpace(item))
+	}
+	return true
+}
+
+// column returns the length of the line written last.
+func (rc *renderContext) column() int {
+	s := rc.sb.String()
+	return len(s) - strings.LastIndexByte(s, '\n') - 1
+}
@@ -0,0 +1,98 @@
+package sqlbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPretty(t *testing.T) {
+	q := Select("u.id", "r.name").From("users").As("u").
+		InnerJoin("roles").As("r").On("r.id", "u.role_id").
+		LeftJoin("teams").On("teams.id", "u.team_id").
+		Where("u.id", In(3)).And("u.deleted_at", IsNull).Or("r.name", Like).
+		OrderBy(Desc, "u.id", "r.name")
+
+	t.Run("case=select", func(t *testing.T) {
+		require.Equal(t, `SELECT u.id, r.name
+FROM users AS u
+  INNER JOIN roles AS r ON r.id = u.role_id
+  LEFT JOIN teams       ON teams.id = u.team_id
+WHERE u.id IN ($1, $2, $3)
+  AND u.deleted_at IS NULL
+   OR r.name LIKE $4
+ORDER BY u.id, r.name DESC`, q.Pretty())
+	})
+
+	t.Run("case=format", func(t *testing.T) {
+		s, err := q.Build(WithDialect(MySQL), WithFormat(Format{Indent: "\t", Lowercase: true}))
+		require.NoError(t, err)
+		require.Equal(t, `select u.id, r.name
+from users as u
+	inner join roles as r on r.id = u.role_id
+	left join teams       on teams.id = u.team_id
+where u.id in (?, ?, ?)
+  and u.deleted_at is null
+   or r.name like ?
+order by u.id, r.name desc`, s)
+
+		require.Equal(t, `SELECT
+    id,
+    name,
+    email
+FROM users`, Pretty(Select("id", "name", "email").From("users"), WithFormat(Format{Indent: "    ", Width: 16})))
+	})
+
+	t.Run("case=modifying statements", func(t *testing.T) {
+		require.Equal(t, `INSERT INTO users (id, name)
+VALUES
+  ($1, $2),
+  ($3, $4)
+RETURNING id`, Insert("id", "name").Into("users").Values(1, "a").Values(2, "b").Returning("id").Pretty())
+
+		require.Equal(t, `INSERT INTO users (id, name)
+SELECT id, name
+FROM accounts
+WHERE id < $1`, Insert("id", "name").Into("users").Select("id", "name").From("accounts").Where("id", LessThan).Pretty())
+
+		require.Equal(t, `UPDATE users
+SET
+  display_name = $1,
+  email_address = $2,
+  last_modified_at = $3,
+  last_modified_by = $4
+WHERE id = $5`, Update("users").Set("display_name", "email_address", "last_modified_at", "last_modified_by").Where("id", Equals).Pretty())
+
+		require.Equal(t, "DELETE FROM users\nWHERE id = $1", Delete().From("users").Where("id", Equals).Pretty())
+	})
+
+	t.Run("case=definitions", func(t *testing.T) {
+		require.Equal(t, `CREATE TABLE users (
+  id BIGINT PRIMARY KEY,
+  name TEXT
+)`, Pretty(CreateTable("users").Columns(Column("id", BigInt).PrimaryKey(), Column("name", Text)),
+			WithFormat(Format{Lowercase: true})))
+
+		require.Equal(t, `CREATE INDEX i ON users (email)
+WHERE deleted_at IS NULL
+  AND active IS TRUE`, CreateIndex("i").On("users", "email").Where("deleted_at", IsNull).And("active", IsTrue).Pretty())
+
+		require.Equal(t, "ALTER TABLE users ADD COLUMN bio TEXT;\nALTER TABLE users RENAME COLUMN name TO full_name",
+			AlterTable("users").AddColumn(Column("bio", Text)).RenameColumn("name", "full_name").Pretty())
+	})
+
+	t.Run("case=same statement as SQL", func(t *testing.T) {
+		for _, q := range []Statement{
+			q,
+			Insert("id", "name").Into("users").Values(1, "a").Values(2, "b").Returning("id"),
+			Update("users").Set("display_name", "email_address", "last_modified_at", "last_modified_by").Where("id", Equals).Parent().Returning("id"),
+			Delete().From("users").Where("id", NotIn(2)).Or("name", IsNotNull),
+		} {
+			s, err := q.Build(WithFormat(Format{Lowercase: true, Width: 1}))
+			require.NoError(t, err)
+			parsed, err := Parse(s)
+			require.NoError(t, err)
+			require.Equal(t, q.SQL(), parsed.SQL())
+		}
+	})
+}
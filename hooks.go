@@ -0,0 +1,208 @@
+package sqlbuilder
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Kind is the kind of a statement passed to a Hook.
+type Kind string
+
+const (
+	SelectKind Kind = "SELECT"
+	InsertKind Kind = "INSERT"
+	UpdateKind Kind = "UPDATE"
+	DeleteKind Kind = "DELETE"
+)
+
+// Hook inspects and may change a statement before it is validated and
+// rendered, e.g. to apply a policy to every statement. Returning an error
+// rejects the statement and Build returns the error as a *BuildError.
+type Hook func(s *Structure) error
+
+// Hooks is a registry of hooks, run in the order they were registered on
+// every SELECT, INSERT, UPDATE and DELETE rendered with WithHooks. It is
+// safe for concurrent use.
+type Hooks struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+// Register adds the hook to the registry.
+func (h *Hooks) Register(hook Hook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, hook)
+}
+
+// WithHooks runs the hooks of the registry before rendering the statement.
+// Pass it to the executor to apply the hooks to every statement it runs.
+func WithHooks(h *Hooks) RenderOption {
+	return func(rc *renderContext) {
+		rc.hooks = h
+	}
+}
+
+// Structure is the structured form of a statement passed to a Hook. It
+// belongs to a copy of the statement, so changes made by a hook only apply
+// to the statement being rendered.
+type Structure struct {
+	Kind Kind
+	// Context is the context given with WithContext.
+	Context context.Context
+	// Dialect is the dialect the statement is rendered for.
+	Dialect *Dialect
+
+	q queryHelper
+}
+
+// Table returns the table of the statement.
+func (s *Structure) Table() string {
+	return s.q.GetTable()
+}
+
+// SetTable changes the table of the statement.
+func (s *Structure) SetTable(table string) {
+	switch b := s.q.(type) {
+	case *SelectBuilder:
+		b.table = table
+	case *InsertBuilder:
+		b.table = table
+	case *UpdateBuilder:
+		b.table = table
+	case *DeleteBuilder:
+		b.table = table
+	}
+}
+
+// Alias returns the alias of the table of the statement, if any.
+func (s *Structure) Alias() string {
+	return s.q.GetAlias()
+}
+
+// Columns returns the columns selected, inserted or set by the statement.
+func (s *Structure) Columns() []string {
+	return slices.Clone(s.q.GetColumns())
+}
+
+// Joins returns the joins of the statement. Use Join.Filter to restrict the
+// rows of a joined table.
+func (s *Structure) Joins() []*Join {
+	return s.q.GetJoins()
+}
+
+// Where returns the first condition of the where chain, if any.
+func (s *Structure) Where() *WhereCondition {
+	return s.q.GetWhere()
+}
+
+// Filter adds a condition on a bound value which has to hold in addition
+// to the where chain of the statement. It is rendered first and the where
+// chain is put in parentheses when it contains OR. An unqualified column is
+// qualified with the alias of the table when the statement has joins.
+// Filter has no effect on an INSERT.
+func (s *Structure) Filter(column string, operator Operator, values ...any) {
+	if len(s.q.GetJoins()) != 0 && !strings.Contains(column, ".") {
+		column = qualify(s.Alias(), s.Table(), column)
+	}
+	c := and(Cond(column, operator, values...), s.q.GetWhere())
+
+	switch b := s.q.(type) {
+	case *SelectBuilder:
+		b.WhereBuilder = &WhereBuilder[SelectFromQuery]{parent: b, where: c}
+	case *UpdateBuilder:
+		// c holds the primary key of UpdateStruct.
+		b.key = nil
+		b.WhereBuilder = &WhereBuilder[UpdateReturningQuery]{parent: b, where: c}
+	case *DeleteBuilder:
+		b.WhereBuilder = &WhereBuilder[DeleteFromQuery]{parent: b, where: c}
+	}
+}
+
+// SetValue sets the column to the bound value in every row of an INSERT. A
+// column the INSERT doesn't name is added before its columns. An INSERT
+// naming the column with a placeholder in place of its value fails to build
+// with ErrUnsupportedStatement, since the value would come from the caller.
+// SetValue has no effect on other statements.
+func (s *Structure) SetValue(column string, value any) {
+	ib, ok := s.q.(*InsertBuilder)
+	if !ok || ib.s != nil {
+		return
+	}
+	if i := slices.Index(ib.preset, column); i >= 0 {
+		ib.presetValues[i] = value
+		return
+	}
+	if i := slices.Index(ib.columns, column); i >= 0 {
+		placeholder := len(ib.values) == 0
+		for _, row := range ib.values {
+			if i < len(row) {
+				row[i] = value
+			} else {
+				placeholder = true
+			}
+		}
+		if placeholder && ib.err == nil {
+			ib.err = fmt.Errorf("%w: placeholder for column %s set by a hook", ErrUnsupportedStatement, column)
+		}
+		return
+	}
+	ib.preset = append(ib.preset, column)
+	ib.presetValues = append(ib.presetValues, value)
+}
+
+// Tag adds a key/value pair to the comment rendered after the statement.
+func (s *Structure) Tag(key, value string) {
+	s.q.(Statement).Tag(key, value)
+}
+
+// qualify prefixes the column with the alias of the table, or with the
+// table when it has no alias.
+func qualify(alias, table, column string) string {
+	if alias == "" {
+		alias = table
+	}
+	return alias + "." + column
+}
+
+// hook runs the hooks on a copy of q and returns the copy. Statements other
+// than SELECT, INSERT, UPDATE and DELETE are returned as they are.
+func (rc *renderContext) hook(q any) (any, error) {
+	if rc.hooks == nil {
+		return q, nil
+	}
+	rc.hooks.mu.RLock()
+	hooks := slices.Clone(rc.hooks.hooks)
+	rc.hooks.mu.RUnlock()
+	if len(hooks) == 0 {
+		return q, nil
+	}
+
+	s := &Structure{Context: rc.ctx, Dialect: rc.dialect}
+	switch b := statement(q).(type) {
+	case *SelectBuilder:
+		s.Kind, s.q = SelectKind, b.clone()
+	case *InsertBuilder:
+		s.Kind, s.q = InsertKind, b.clone()
+	case *UpdateBuilder:
+		s.Kind, s.q = UpdateKind, b.clone()
+	case *DeleteBuilder:
+		s.Kind, s.q = DeleteKind, b.clone()
+	default:
+		return q, nil
+	}
+
+	for _, h := range hooks {
+		if err := h(s); err != nil {
+			return nil, &BuildError{Statement: string(s.Kind), Table: s.Table(), Err: err}
+		}
+	}
+	if _, ok := q.(*InsertBuilder); ok && s.Kind == SelectKind {
+		// Keep rendering the INSERT, its tags are those of the statement.
+		return s.q.GetParent(), nil
+	}
+	return s.q, nil
+}
@@ -0,0 +1,105 @@
+package sqlbuilder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	t.Run("case=filter", func(t *testing.T) {
+		hooks := &Hooks{}
+		hooks.Register(func(s *Structure) error {
+			if s.Kind == InsertKind {
+				return nil
+			}
+			s.Filter("org_id", Equals, 7)
+			for _, j := range s.Joins() {
+				j.Filter(j.GetAlias()+".org_id", Equals, 7)
+			}
+			return nil
+		})
+
+		q := Select("u.id").From("users").As("u").
+			LeftJoin("roles").As("r").On("r.id", "u.role_id").
+			Where("u.name", Equals).Or("u.email", In(2))
+		var args []any
+		s, err := q.Build(WithHooks(hooks), CaptureArgs(&args))
+		require.NoError(t, err)
+		require.Equal(t, "SELECT u.id FROM users AS u LEFT JOIN roles AS r ON r.id = u.role_id AND r.org_id = $1 "+
+			"WHERE u.org_id = $2 AND (u.name = $3 OR u.email IN ($4, $5))", s)
+		require.Equal(t, []any{7, 7}, args)
+
+		// The statement itself is left untouched.
+		require.Equal(t, "SELECT u.id FROM users AS u LEFT JOIN roles AS r ON r.id = u.role_id WHERE u.name = $1 OR u.email IN ($2, $3)", q.SQL())
+
+		s, err = Delete().From("users").Where("id", Equals).And("active", IsTrue).Build(WithHooks(hooks))
+		require.NoError(t, err)
+		require.Equal(t, "DELETE FROM users WHERE org_id = $1 AND id = $2 AND active IS TRUE", s)
+
+		s, err = Update("users").Set("name").Build(WithHooks(hooks), WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET name = ? WHERE org_id = ?", s)
+
+		s, err = Insert("id").Into("users").Build(WithHooks(hooks))
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO users (id) VALUES ($1)", s)
+	})
+
+	t.Run("case=structure", func(t *testing.T) {
+		type key struct{}
+		hooks := &Hooks{}
+		hooks.Register(func(s *Structure) error {
+			require.Equal(t, UpdateKind, s.Kind)
+			require.Equal(t, "users", s.Table())
+			require.Equal(t, []string{"name"}, s.Columns())
+			require.Equal(t, "id", s.Where().ColumnA)
+			require.Equal(t, "v", s.Context.Value(key{}))
+			s.SetTable("archive.users")
+			s.Tag("hook", "1")
+			return nil
+		})
+
+		ctx := context.WithValue(context.Background(), key{}, "v")
+		s, err := Update("users").Set("name").Where("id", Equals).Build(WithHooks(hooks), WithContext(ctx))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE archive.users SET name = $1 WHERE id = $2 /*hook='1'*/", s)
+
+		// Definitions are not passed to hooks.
+		_, err = DropTable("users").Build(WithHooks(hooks))
+		require.NoError(t, err)
+	})
+
+	t.Run("case=reject", func(t *testing.T) {
+		errUnscoped := errors.New("unscoped delete")
+		hooks := &Hooks{}
+		hooks.Register(func(s *Structure) error {
+			if s.Kind == DeleteKind && s.Where() == nil {
+				return errUnscoped
+			}
+			return nil
+		})
+
+		_, err := Delete().From("users").Build(WithHooks(hooks))
+		require.ErrorIs(t, err, errUnscoped)
+		var be *BuildError
+		require.ErrorAs(t, err, &be)
+		require.Equal(t, "DELETE", be.Statement)
+		require.Equal(t, "users", be.Table)
+
+		_, err = Delete().From("users").Where("id", Equals).Build(WithHooks(hooks))
+		require.NoError(t, err)
+	})
+
+	t.Run("case=validated after hooks", func(t *testing.T) {
+		hooks := &Hooks{}
+		hooks.Register(func(s *Structure) error {
+			s.Filter("id", In(0))
+			return nil
+		})
+		_, err := Select().From("users").Where("a", Equals).Or("b", Equals).Build(WithHooks(hooks))
+		require.ErrorIs(t, err, ErrEmptyIn)
+	})
+}
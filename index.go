@@ -0,0 +1,525 @@
+package sqlbuilder
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// IndexMethod is the access method of an index.
+type IndexMethod string
+
+const (
+	BTree IndexMethod = "btree"
+	GIN   IndexMethod = "gin"
+	GiST  IndexMethod = "gist"
+	Hash  IndexMethod = "hash"
+)
+
+type (
+	CreateIndexBuilder struct {
+		name         string
+		table        string
+		unique       bool
+		concurrently bool
+		ifNotExists  bool
+		method       IndexMethod
+		columns      []*IndexColumnDef
+		include      []string
+		where        *WhereCondition
+		predicate    string
+		tags         Tags
+	}
+	DropIndexBuilder struct {
+		name         string
+		table        string
+		concurrently bool
+		ifExists     bool
+		cascade      bool
+		tags         Tags
+	}
+	// IndexColumnDef is a column or an expression of an index.
+	IndexColumnDef struct {
+		expr       string
+		order      OrderBy
+		nulls      string
+		opClass    string
+		expression bool
+	}
+)
+
+var (
+	_ Statement    = (*CreateIndexBuilder)(nil)
+	_ selfRenderer = (*CreateIndexBuilder)(nil)
+	_ Statement    = (*DropIndexBuilder)(nil)
+	_ selfRenderer = (*DropIndexBuilder)(nil)
+)
+
+// IndexColumn is a column of an index. Anything but a plain column name,
+// such as lower(email), is indexed as an expression.
+func IndexColumn(column string) *IndexColumnDef {
+	return &IndexColumnDef{expr: column, expression: !isIdentifier(column)}
+}
+
+func (c *IndexColumnDef) Asc() *IndexColumnDef {
+	c.order = Asc
+	return c
+}
+
+func (c *IndexColumnDef) Desc() *IndexColumnDef {
+	c.order = Desc
+	return c
+}
+
+func (c *IndexColumnDef) NullsFirst() *IndexColumnDef {
+	c.nulls = "FIRST"
+	return c
+}
+
+func (c *IndexColumnDef) NullsLast() *IndexColumnDef {
+	c.nulls = "LAST"
+	return c
+}
+
+// OpClass sets the operator class of the column, e.g. gin_trgm_ops.
+func (c *IndexColumnDef) OpClass(name string) *IndexColumnDef {
+	c.opClass = name
+	return c
+}
+
+func (c *IndexColumnDef) render(sb *strings.Builder) {
+	if c.expression {
+		sb.WriteString("(" + c.expr + ")")
+	} else {
+		sb.WriteString(c.expr)
+	}
+	if c.opClass != "" {
+		sb.WriteString(" " + c.opClass)
+	}
+	if c.order != "" {
+		sb.WriteString(" " + string(c.order))
+	}
+	if c.nulls != "" {
+		sb.WriteString(" NULLS " + c.nulls)
+	}
+}
+
+// CreateIndex creates the index with the given name.
+//
+//	CreateIndex("users_email_idx").
+//		On("users", "lower(email)").
+//		Unique().
+//		Where("deleted_at", IsNull)
+func CreateIndex(name string) *CreateIndexBuilder {
+	return &CreateIndexBuilder{name: name}
+}
+
+// On sets the indexed table and adds columns or expressions to the index,
+// see IndexColumn.
+func (b *CreateIndexBuilder) On(table string, columns ...string) *CreateIndexBuilder {
+	b.table = table
+	for _, c := range columns {
+		b.columns = append(b.columns, IndexColumn(c))
+	}
+	return b
+}
+
+// Columns adds columns with a direction or an operator class to the index.
+func (b *CreateIndexBuilder) Columns(columns ...*IndexColumnDef) *CreateIndexBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+func (b *CreateIndexBuilder) Unique() *CreateIndexBuilder {
+	b.unique = true
+	return b
+}
+
+// Concurrently builds the index without locking writes to the table.
+func (b *CreateIndexBuilder) Concurrently() *CreateIndexBuilder {
+	b.concurrently = true
+	return b
+}
+
+func (b *CreateIndexBuilder) IfNotExists() *CreateIndexBuilder {
+	b.ifNotExists = true
+	return b
+}
+
+func (b *CreateIndexBuilder) Using(method IndexMethod) *CreateIndexBuilder {
+	b.method = method
+	return b
+}
+
+// Include adds non key columns to the index, making it a covering index.
+func (b *CreateIndexBuilder) Include(columns ...string) *CreateIndexBuilder {
+	b.include = append(b.include, columns...)
+	return b
+}
+
+// Where makes the index partial. Its values are inlined as literals since
+// indexes can't take bound values.
+func (b *CreateIndexBuilder) Where(column string, operator Operator, values ...any) *CreateIndexBuilder {
+	b.where = &WhereCondition{ColumnA: column, Op: operator, values: values}
+	return b
+}
+
+// WhereExpr makes the index partial with the SQL expression expr, which is
+// rendered as is and combined with the conditions of Where.
+func (b *CreateIndexBuilder) WhereExpr(expr string) *CreateIndexBuilder {
+	b.predicate = expr
+	return b
+}
+
+func (b *CreateIndexBuilder) And(column string, operator Operator, values ...any) *CreateIndexBuilder {
+	return b.chain(column, operator, values, And)
+}
+
+func (b *CreateIndexBuilder) Or(column string, operator Operator, values ...any) *CreateIndexBuilder {
+	return b.chain(column, operator, values, Or)
+}
+
+func (b *CreateIndexBuilder) chain(column string, operator Operator, values []any, lo LogicalOperator) *CreateIndexBuilder {
+	if b.where == nil {
+		return b.Where(column, operator, values...)
+	}
+	WhereOperator(b.where, operator, column, lo)
+	last := b.where
+	for last.next != nil {
+		last = last.next
+	}
+	last.values = values
+	return b
+}
+
+// When calls f with the builder when cond is true.
+func (b *CreateIndexBuilder) When(cond bool, f func(b *CreateIndexBuilder)) *CreateIndexBuilder {
+	if cond {
+		f(b)
+	}
+	return b
+}
+
+func (b *CreateIndexBuilder) Clone() *CreateIndexBuilder {
+	c := *b
+	c.columns = nil
+	for _, col := range b.columns {
+		n := *col
+		c.columns = append(c.columns, &n)
+	}
+	c.include = slices.Clone(b.include)
+	c.where = b.where.Clone()
+	c.tags = maps.Clone(b.tags)
+	return &c
+}
+
+func (b *CreateIndexBuilder) SQL() string {
+	return SQL(b)
+}
+
+func (b *CreateIndexBuilder) Pretty() string {
+	return Pretty(b)
+}
+
+func (b *CreateIndexBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(b, opts...)
+}
+
+func (b *CreateIndexBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(b, opts...)
+}
+
+func (b *CreateIndexBuilder) Tag(key, value string) Statement {
+	b.tags.set(key, value)
+	return b
+}
+
+func (b *CreateIndexBuilder) GetTags() Tags {
+	return b.tags
+}
+
+func (b *CreateIndexBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(b, opts...)
+}
+
+func (b *CreateIndexBuilder) Err() error {
+	return Err(b)
+}
+
+func (b *CreateIndexBuilder) Args() []any {
+	return nil
+}
+
+func (b *CreateIndexBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(b, opts...)
+}
+
+func (b *CreateIndexBuilder) render(rc *renderContext) {
+	sb := &rc.sb
+	sb.WriteString("CREATE ")
+	if b.unique {
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString("INDEX ")
+	if b.concurrently {
+		sb.WriteString("CONCURRENTLY ")
+	}
+	if b.ifNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	sb.WriteString(b.name)
+	if b.method != "" && rc.dialect == MySQL {
+		sb.WriteString(" USING " + strings.ToUpper(string(b.method)))
+	}
+	sb.WriteString(" ON ")
+	sb.WriteString(b.table)
+	if b.method != "" && rc.dialect != MySQL {
+		sb.WriteString(" USING " + string(b.method))
+	}
+	sb.WriteString(" (")
+	for i, c := range b.columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		c.render(sb)
+	}
+	sb.WriteString(")")
+	if len(b.include) != 0 {
+		sb.WriteString(" INCLUDE (")
+		sb.WriteString(strings.Join(b.include, ", "))
+		sb.WriteString(")")
+	}
+	if b.where == nil && b.predicate == "" {
+		return
+	}
+	rc.clause("WHERE ")
+	switch {
+	case b.where != nil && b.predicate != "":
+		sb.WriteString("(")
+		b.renderWhere(rc)
+		sb.WriteString(") AND (" + b.predicate + ")")
+	case b.where != nil:
+		b.renderWhere(rc)
+	default:
+		sb.WriteString(b.predicate)
+	}
+}
+
+func (b *CreateIndexBuilder) renderWhere(rc *renderContext) {
+	inline := rc.inline
+	rc.inline = true
+	whereSQL(rc, b.where)
+	rc.inline = inline
+}
+
+func (b *CreateIndexBuilder) validate(d *Dialect) error {
+	var errs []error
+	add := func(column string, err error) {
+		errs = append(errs, &BuildError{
+			Statement: "CREATE INDEX",
+			Table:     b.table,
+			Column:    column,
+			Err:       err,
+		})
+	}
+	unsupported := func(column, feature string) {
+		add(column, fmt.Errorf("%w: %s", d.unsupported(), feature))
+	}
+
+	if b.table == "" {
+		add("", ErrMissingTable)
+	}
+	if len(b.columns) == 0 {
+		add("", ErrNoColumns)
+	}
+	for c := b.where; c != nil; c = c.next {
+		count := 1
+		switch op := c.Op.get().(type) {
+		case BasicOperator:
+			if op.unary() {
+				count = 0
+			}
+		case SpecialOperator:
+			count, _ = op()
+			if count == 0 {
+				add(c.ColumnA, ErrEmptyIn)
+			}
+		}
+		if len(c.values) != count {
+			add(c.ColumnA, fmt.Errorf("%w: %d values for %d placeholders", ErrMissingValue, len(c.values), count))
+		}
+	}
+
+	if d == nil || d == Postgres {
+		return errors.Join(errs...)
+	}
+	if b.concurrently {
+		unsupported("", "CONCURRENTLY")
+	}
+	if b.ifNotExists && d != SQLite {
+		unsupported("", "IF NOT EXISTS")
+	}
+	if b.method != "" && (d != MySQL || (b.method != BTree && b.method != Hash)) {
+		unsupported("", "USING "+string(b.method))
+	}
+	if len(b.include) != 0 && d != SQLServer {
+		unsupported("", "INCLUDE")
+	}
+	if (b.where != nil || b.predicate != "") && d == MySQL {
+		unsupported("", "partial index")
+	}
+	for _, c := range b.columns {
+		if c.opClass != "" {
+			unsupported(c.expr, "operator class")
+		}
+		if c.nulls != "" {
+			unsupported(c.expr, "NULLS "+c.nulls)
+		}
+		if c.expression && d == SQLServer {
+			unsupported(c.expr, "expression index")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DropIndex drops the index with the given name.
+func DropIndex(name string) *DropIndexBuilder {
+	return &DropIndexBuilder{name: name}
+}
+
+// On sets the table of the index, required by MySQL and SQL Server.
+func (b *DropIndexBuilder) On(table string) *DropIndexBuilder {
+	b.table = table
+	return b
+}
+
+func (b *DropIndexBuilder) Concurrently() *DropIndexBuilder {
+	b.concurrently = true
+	return b
+}
+
+func (b *DropIndexBuilder) IfExists() *DropIndexBuilder {
+	b.ifExists = true
+	return b
+}
+
+// Cascade also drops the objects depending on the index.
+func (b *DropIndexBuilder) Cascade() *DropIndexBuilder {
+	b.cascade = true
+	return b
+}
+
+// When calls f with the builder when cond is true.
+func (b *DropIndexBuilder) When(cond bool, f func(b *DropIndexBuilder)) *DropIndexBuilder {
+	if cond {
+		f(b)
+	}
+	return b
+}
+
+func (b *DropIndexBuilder) Clone() *DropIndexBuilder {
+	c := *b
+	c.tags = maps.Clone(b.tags)
+	return &c
+}
+
+func (b *DropIndexBuilder) SQL() string {
+	return SQL(b)
+}
+
+func (b *DropIndexBuilder) Pretty() string {
+	return Pretty(b)
+}
+
+func (b *DropIndexBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(b, opts...)
+}
+
+func (b *DropIndexBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(b, opts...)
+}
+
+func (b *DropIndexBuilder) Tag(key, value string) Statement {
+	b.tags.set(key, value)
+	return b
+}
+
+func (b *DropIndexBuilder) GetTags() Tags {
+	return b.tags
+}
+
+func (b *DropIndexBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(b, opts...)
+}
+
+func (b *DropIndexBuilder) Err() error {
+	return Err(b)
+}
+
+func (b *DropIndexBuilder) Args() []any {
+	return nil
+}
+
+func (b *DropIndexBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(b, opts...)
+}
+
+func (b *DropIndexBuilder) render(rc *renderContext) {
+	sb := &rc.sb
+	sb.WriteString("DROP INDEX ")
+	if b.concurrently {
+		sb.WriteString("CONCURRENTLY ")
+	}
+	if b.ifExists {
+		sb.WriteString("IF EXISTS ")
+	}
+	sb.WriteString(b.name)
+	if b.table != "" && (rc.dialect == MySQL || rc.dialect == SQLServer) {
+		sb.WriteString(" ON " + b.table)
+	}
+	if b.cascade {
+		sb.WriteString(" CASCADE")
+	}
+}
+
+func (b *DropIndexBuilder) validate(d *Dialect) error {
+	var errs []error
+	add := func(err error) {
+		errs = append(errs, &BuildError{Statement: "DROP INDEX", Table: b.table, Err: err})
+	}
+
+	if b.name == "" {
+		add(fmt.Errorf("%w: index without name", ErrInvalidAction))
+	}
+	if d == nil || d == Postgres {
+		return errors.Join(errs...)
+	}
+	if b.table == "" && (d == MySQL || d == SQLServer) {
+		add(ErrMissingTable)
+	}
+	if b.concurrently {
+		add(fmt.Errorf("%w: CONCURRENTLY", d.unsupported()))
+	}
+	if b.ifExists && d == MySQL {
+		add(fmt.Errorf("%w: IF EXISTS", d.unsupported()))
+	}
+	if b.cascade && !d.cascade {
+		add(fmt.Errorf("%w: CASCADE", d.unsupported()))
+	}
+	return errors.Join(errs...)
+}
+
+// isIdentifier reports whether s is a plain, possibly qualified, name.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r != '_' && r != '.' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
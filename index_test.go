@@ -0,0 +1,106 @@
+package sqlbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIndex(t *testing.T) {
+	t.Run("case=partial", func(t *testing.T) {
+		s, err := CreateIndex("users_email_idx").
+			On("users", "lower(email)").
+			Unique().
+			Where("deleted_at", IsNull).
+			And("status", In(2), "active", "o'neil").
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE UNIQUE INDEX users_email_idx ON users ((lower(email))) "+
+			"WHERE deleted_at IS NULL AND status IN ('active', 'o''neil')", s)
+	})
+
+	t.Run("case=expression predicate", func(t *testing.T) {
+		q := CreateIndex("i").On("users", "email").WhereExpr("deleted_at IS NULL")
+		require.Equal(t, "CREATE INDEX i ON users (email) WHERE deleted_at IS NULL", q.SQL())
+		q.Where("active", IsTrue).Or("admin", IsTrue)
+		require.Equal(t, "CREATE INDEX i ON users (email) WHERE (active IS TRUE OR admin IS TRUE) AND (deleted_at IS NULL)", q.SQL())
+	})
+
+	t.Run("case=postgres", func(t *testing.T) {
+		s, err := CreateIndex("posts_search_idx").
+			Concurrently().IfNotExists().
+			On("posts").
+			Using(GIN).
+			Columns(IndexColumn("title").OpClass("gin_trgm_ops")).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX CONCURRENTLY IF NOT EXISTS posts_search_idx ON posts USING gin (title gin_trgm_ops)", s)
+
+		s, err = CreateIndex("posts_created_idx").
+			On("posts", "author_id").
+			Columns(IndexColumn("created_at").Desc().NullsLast()).
+			Include("title").
+			Where("published", IsTrue).Or("views", GreaterThan, 100).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX posts_created_idx ON posts (author_id, created_at DESC NULLS LAST) "+
+			"INCLUDE (title) WHERE published IS TRUE OR views > 100", s)
+	})
+
+	t.Run("case=dialects", func(t *testing.T) {
+		s, err := CreateIndex("users_name_idx").On("users", "name").Using(Hash).Build(WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX users_name_idx USING HASH ON users (name)", s)
+
+		s, err = CreateIndex("users_email_idx").On("users", "email").Include("name").
+			Where("active", Equals, true).Build(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX users_email_idx ON users (email) INCLUDE (name) WHERE active = 1", s)
+
+		s, err = CreateIndex("users_email_idx").IfNotExists().On("users", "email").
+			Where("role", Equals, "admin").Build(WithDialect(SQLite))
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX IF NOT EXISTS users_email_idx ON users (email) WHERE role = 'admin'", s)
+
+		for _, q := range []*CreateIndexBuilder{
+			CreateIndex("i").On("users", "email").Where("deleted_at", IsNull),
+			CreateIndex("i").On("users", "email").Concurrently(),
+			CreateIndex("i").On("users", "email").Using(GIN),
+			CreateIndex("i").On("users", "email").Include("name"),
+		} {
+			_, err := q.Build(WithDialect(MySQL))
+			require.ErrorIs(t, err, ErrUnsupportedByDialect)
+		}
+		_, err = CreateIndex("i").On("users", "lower(email)").Build(WithDialect(SQLServer))
+		require.ErrorIs(t, err, ErrUnsupportedByDialect)
+	})
+
+	t.Run("case=clone", func(t *testing.T) {
+		q := CreateIndex("i").On("users", "email").Where("deleted_at", IsNull)
+		c := q.Clone().And("active", IsTrue)
+		require.Equal(t, "CREATE INDEX i ON users (email) WHERE deleted_at IS NULL", q.SQL())
+		require.Equal(t, "CREATE INDEX i ON users (email) WHERE deleted_at IS NULL AND active IS TRUE", c.SQL())
+	})
+
+	t.Run("case=invalid", func(t *testing.T) {
+		require.ErrorIs(t, CreateIndex("i").Err(), ErrMissingTable)
+		require.ErrorIs(t, CreateIndex("i").On("users").Err(), ErrNoColumns)
+		require.ErrorIs(t, CreateIndex("i").On("users", "email").Where("role", Equals).Err(), ErrMissingValue)
+		require.ErrorIs(t, CreateIndex("i").On("users", "email").Where("role", In(0)).Err(), ErrEmptyIn)
+	})
+}
+
+func TestDropIndex(t *testing.T) {
+	s, err := DropIndex("users_email_idx").Concurrently().IfExists().Build()
+	require.NoError(t, err)
+	require.Equal(t, "DROP INDEX CONCURRENTLY IF EXISTS users_email_idx", s)
+
+	s, err = DropIndex("users_email_idx").On("users").Build(WithDialect(MySQL))
+	require.NoError(t, err)
+	require.Equal(t, "DROP INDEX users_email_idx ON users", s)
+
+	_, err = DropIndex("users_email_idx").Build(WithDialect(SQLServer))
+	require.ErrorIs(t, err, ErrMissingTable)
+	_, err = DropIndex("users_email_idx").Concurrently().Build(WithDialect(SQLite))
+	require.ErrorIs(t, err, ErrUnsupportedByDialect)
+}
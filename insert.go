@@ -1,8 +1,8 @@
 package sqlbuilder
 
 import (
-	"strconv"
-	"strings"
+	"maps"
+	"slices"
 )
 
 type (
@@ -13,7 +13,10 @@ type (
 		Values(values ...any) InsertIntoQuery
 		Returning(columns ...string) InsertIntoQuery
 		SelectQuery
-		SQL() string
+		// When calls f with the insert when cond is true.
+		When(cond bool, f func(q InsertIntoQuery)) InsertIntoQuery
+		Clone() InsertIntoQuery
+		Statement
 	}
 	InsertQuery interface {
 		Insert(columns ...string) Into[InsertIntoQuery]
@@ -22,13 +25,23 @@ type (
 		table     string
 		columns   []string
 		returning []string
-		values    []any
+		values    [][]any
 		as        string
 		s         SelectQuery
+		err       error
+		tags      Tags
+		// preset columns are set to the same bound value in every row,
+		// see Structure.SetValue.
+		preset       []string
+		presetValues []any
 	}
 )
 
-var _ InsertQuery = &InsertBuilder{}
+var (
+	_ InsertQuery     = (*InsertBuilder)(nil)
+	_ InsertIntoQuery = (*InsertBuilder)(nil)
+	_ queryHelper     = (*InsertBuilder)(nil)
+)
 
 func (ib *InsertBuilder) Insert(columns ...string) Into[InsertIntoQuery] {
 	ib.columns = columns
@@ -45,8 +58,18 @@ func (ib *InsertBuilder) Into(table string) InsertIntoQuery {
 	return ib
 }
 
+func (ib *InsertBuilder) When(cond bool, f func(q InsertIntoQuery)) InsertIntoQuery {
+	if cond {
+		f(ib)
+	}
+	return ib
+}
+
+// Values binds a row of values to the insert. Calling it more than once
+// inserts multiple rows, calling it without values adds a row of
+// placeholders.
 func (ib *InsertBuilder) Values(values ...any) InsertIntoQuery {
-	ib.values = values
+	ib.values = append(ib.values, values)
 	return ib
 }
 
@@ -62,33 +85,120 @@ func (ib *InsertBuilder) Returning(columns ...string) InsertIntoQuery {
 	return ib
 }
 
-func (ib *InsertBuilder) SQL() string {
-	var sb strings.Builder
-	sb.WriteString("INSERT ")
-	sb.WriteString("INTO ")
-	sb.WriteString(ib.table)
-
-	sb.WriteString(" (")
-	sb.WriteString(strings.TrimSpace(strings.Join(ib.columns, ", ")))
-	sb.WriteString(")")
-
-	if ib.s == nil {
-		sb.WriteString(" VALUES ")
-		sb.WriteString("(")
-
-		cols := []string{}
-		for i := 1; i <= len(ib.columns); i++ {
-			cols = append(cols, "$"+strconv.Itoa(i))
-		}
+// Clone returns a deep copy of the insert, including its SELECT if any.
+func (ib *InsertBuilder) Clone() InsertIntoQuery {
+	return ib.clone()
+}
 
-		sb.WriteString(strings.TrimSpace(strings.Join(cols, ", ")))
-		sb.WriteString(")")
+func (ib *InsertBuilder) clone() *InsertBuilder {
+	c := &InsertBuilder{
+		table:     ib.table,
+		columns:   slices.Clone(ib.columns),
+		returning: slices.Clone(ib.returning),
+		as:        ib.as,
+		err:       ib.err,
+		tags:      maps.Clone(ib.tags),
+
+		preset:       slices.Clone(ib.preset),
+		presetValues: slices.Clone(ib.presetValues),
+	}
+	if ib.values != nil {
+		c.values = make([][]any, len(ib.values))
+		for i, row := range ib.values {
+			c.values[i] = slices.Clone(row)
+		}
 	}
+	if s, ok := ib.s.(*SelectBuilder); ok {
+		c.s = s.cloneWithParent(c)
+	}
+	return c
+}
 
-	if ib.returning != nil {
-		sb.WriteString(" RETURNING ")
-		sb.WriteString(strings.TrimSpace(strings.Join(ib.returning, ", ")))
+func (ib *InsertBuilder) SQL() string {
+	return SQL(ib)
+}
+
+func (ib *InsertBuilder) Pretty() string {
+	return Pretty(ib)
+}
+
+func (ib *InsertBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(ib, opts...)
+}
+
+func (ib *InsertBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(ib, opts...)
+}
+
+func (ib *InsertBuilder) Tag(key, value string) Statement {
+	ib.tags.set(key, value)
+	return ib
+}
+
+func (ib *InsertBuilder) GetTags() Tags {
+	return ib.tags
+}
+
+func (ib *InsertBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(ib, opts...)
+}
+
+func (ib *InsertBuilder) Err() error {
+	return Err(ib)
+}
+
+func (ib *InsertBuilder) Args() []any {
+	return Args(ib)
+}
+
+func (ib *InsertBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(ib, opts...)
+}
+
+// GetAlias implements queryHelper
+func (ib *InsertBuilder) GetAlias() string {
+	return ""
+}
+
+// GetColumns implements queryHelper
+func (ib *InsertBuilder) GetColumns() []string {
+	if len(ib.preset) == 0 {
+		return ib.columns
 	}
+	return append(slices.Clone(ib.preset), ib.columns...)
+}
+
+// GetJoins implements queryHelper
+func (ib *InsertBuilder) GetJoins() []*Join {
+	return nil
+}
+
+// GetOrderBy implements queryHelper
+func (ib *InsertBuilder) GetOrderBy() *Sort {
+	return nil
+}
+
+// GetParent implements queryHelper
+func (ib *InsertBuilder) GetParent() any {
+	return nil
+}
+
+// GetReturning implements queryHelper
+func (ib *InsertBuilder) GetReturning() []string {
+	return ib.returning
+}
+
+// GetPosition implements queryHelper
+func (ib *InsertBuilder) GetPosition() *int {
+	return new(int)
+}
+
+// GetTable implements queryHelper
+func (ib *InsertBuilder) GetTable() string {
+	return ib.table
+}
 
-	return sb.String()
+// GetWhere implements queryHelper
+func (ib *InsertBuilder) GetWhere() *WhereCondition {
+	return nil
 }
@@ -0,0 +1,126 @@
+// Package fakedb is a database/sql driver for tests. It records every
+// statement it receives and answers them through a handler.
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+)
+
+type (
+	// Result is returned by a Handler for a single statement.
+	Result struct {
+		Columns      []string
+		Rows         [][]driver.Value
+		RowsAffected int64
+		Err          error
+	}
+	Handler func(query string, args []any) Result
+
+	// Call is a statement received by the driver. Transactions are recorded
+	// as BEGIN, COMMIT and ROLLBACK calls.
+	Call struct {
+		Query string
+		Args  []any
+	}
+
+	DB struct {
+		mu      sync.Mutex
+		calls   []Call
+		handler Handler
+	}
+)
+
+// Open returns a *sql.DB answering statements with h, which may be nil.
+func Open(h Handler) (*sql.DB, *DB) {
+	d := &DB{handler: h}
+	return sql.OpenDB(connector{d}), d
+}
+
+// Calls returns the statements received so far.
+func (d *DB) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Call(nil), d.calls...)
+}
+
+// Queries returns the SQL of the statements received so far.
+func (d *DB) Queries() []string {
+	var queries []string
+	for _, c := range d.Calls() {
+		queries = append(queries, c.Query)
+	}
+	return queries
+}
+
+func (d *DB) handle(query string, args []driver.NamedValue) Result {
+	values := make([]any, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+
+	d.mu.Lock()
+	d.calls = append(d.calls, Call{Query: query, Args: values})
+	d.mu.Unlock()
+
+	if d.handler == nil {
+		return Result{}
+	}
+	return d.handler(query, values)
+}
+
+type (
+	connector struct{ d *DB }
+	conn      struct{ d *DB }
+	tx        struct{ d *DB }
+	rows      struct {
+		columns []string
+		values  [][]driver.Value
+	}
+)
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return conn(c), nil }
+func (c connector) Driver() driver.Driver                        { return c }
+func (c connector) Open(string) (driver.Conn, error)             { return conn(c), nil }
+
+func (c conn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c conn) Close() error                        { return nil }
+func (c conn) Begin() (driver.Tx, error) {
+	if res := c.d.handle("BEGIN", nil); res.Err != nil {
+		return nil, res.Err
+	}
+	return tx(c), nil
+}
+
+func (c conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res := c.d.handle(query, args)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return &rows{columns: res.Columns, values: res.Rows}, nil
+}
+
+func (c conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res := c.d.handle(query, args)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return driver.RowsAffected(res.RowsAffected), nil
+}
+
+func (t tx) Commit() error   { return t.d.handle("COMMIT", nil).Err }
+func (t tx) Rollback() error { return t.d.handle("ROLLBACK", nil).Err }
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package fields maps struct fields to columns through their `db` tags.
+//
+//	ID        int       `db:"id,pk,omitempty"`
+//	Name      string    `db:"name"`
+//	CreatedAt time.Time `db:"created_at,readonly"`
+//
+// Fields tagged `db:"-"` and fields without a tag are ignored, untagged
+// embedded structs are flattened into their parent. Embedded pointers to
+// unexported struct types are ignored.
+package fields
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type Field struct {
+	Column    string
+	Index     []int
+	OmitEmpty bool
+	ReadOnly  bool
+	PK        bool
+}
+
+var cache sync.Map
+
+// Of returns the cached fields of t, which must be a struct type.
+func Of(t reflect.Type) []Field {
+	if fields, ok := cache.Load(t); ok {
+		return fields.([]Field)
+	}
+	fields, _ := cache.LoadOrStore(t, of(t, nil))
+	return fields.([]Field)
+}
+
+func of(t reflect.Type, index []int) []Field {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("db")
+		idx := append(append([]int(nil), index...), i)
+
+		if !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				if !f.IsExported() {
+					// Can't be allocated when scanning, same as encoding/json.
+					continue
+				}
+				ft = ft.Elem()
+			}
+			if f.Anonymous && ft.Kind() == reflect.Struct {
+				fields = append(fields, of(ft, idx)...)
+			}
+			continue
+		}
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		field := Field{Column: name, Index: idx}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				field.OmitEmpty = true
+			case "readonly":
+				field.ReadOnly = true
+			case "pk":
+				field.PK = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// Columns returns the column names of the fields of t.
+func Columns(t reflect.Type) []string {
+	fields := Of(t)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.Column
+	}
+	return columns
+}
+
+// Value returns the value of the field in v and whether it is the zero
+// value. Fields behind a nil embedded pointer are reported as nil.
+func (f Field) Value(v reflect.Value) (any, bool) {
+	for i, x := range f.Index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, true
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v.Interface(), v.IsZero()
+}
+
+// Addr returns a pointer to the field in v, which must be addressable.
+// Nil embedded pointers on the way are allocated.
+func (f Field) Addr(v reflect.Value) any {
+	for i, x := range f.Index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v.Addr().Interface()
+}
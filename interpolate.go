@@ -0,0 +1,48 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"slices"
+)
+
+// InterpolatedPrefix marks a statement rendered by Interpolate. The values
+// inlined by Interpolate are escaped for reading, not for running, so the
+// statement must never be executed.
+const InterpolatedPrefix = "/* interpolated for debugging, do not execute */ "
+
+// Redactor returns the value shown in place of the value bound to column
+// when a statement is interpolated.
+type Redactor func(column string, value any) any
+
+// WithRedactor redacts the values inlined by Interpolate.
+func WithRedactor(r Redactor) RenderOption {
+	return func(rc *renderContext) {
+		rc.redact = r
+	}
+}
+
+// RedactColumns returns a Redactor replacing the values bound to any of the
+// given columns with "[redacted]". NULLs are kept, as they reveal nothing.
+func RedactColumns(columns ...string) Redactor {
+	return func(column string, value any) any {
+		if rv := reflect.ValueOf(value); !rv.IsValid() || rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return value
+		}
+		if !slices.Contains(columns, column) {
+			return value
+		}
+		return "[redacted]"
+	}
+}
+
+// Interpolate renders the statement with its bound values inlined as
+// literals of the dialect, for logging and reproducing bugs. Placeholders
+// without a bound value keep their position. The statement starts with
+// InterpolatedPrefix and must not be executed, use Build and Args instead.
+func Interpolate[T any](q T, opts ...RenderOption) string {
+	rc := newRenderContext(opts...)
+	rc.interpolate = true
+	rc.sb.WriteString(InterpolatedPrefix)
+	renderStatement(rc.prepare(q), rc)
+	return rc.sb.String()
+}
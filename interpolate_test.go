@@ -0,0 +1,53 @@
+package sqlbuilder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Run("case=literals", func(t *testing.T) {
+		type status string
+		name := "o'neil"
+		at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+		q := Insert("a", "b", "c", "d", "e", "f", "g", "h", "i", "j").Into("t").
+			Values(&name, []byte{0xde, 0xad}, at, nil, true, []string{"x", "y"}, json.RawMessage(`{"k":1}`), map[string]int{"n": 2}, status("on"), 1.5)
+
+		require.Equal(t, InterpolatedPrefix+`INSERT INTO t (a, b, c, d, e, f, g, h, i, j) VALUES ('o''neil', '\xdead'::bytea, '2024-05-01 12:30:00Z', NULL, TRUE, ARRAY['x', 'y'], '{"k":1}', '{"n":2}', 'on', 1.5)`, q.Interpolate())
+		require.Equal(t, InterpolatedPrefix+`INSERT INTO t (a, b, c, d, e, f, g, h, i, j) VALUES ('o''neil', X'dead', '2024-05-01 12:30:00Z', NULL, TRUE, '["x","y"]', '{"k":1}', '{"n":2}', 'on', 1.5)`, q.Interpolate(WithDialect(MySQL)))
+		require.Equal(t, InterpolatedPrefix+`INSERT INTO t (a, b, c, d, e, f, g, h, i, j) VALUES (N'o''neil', 0xdead, N'2024-05-01 12:30:00Z', NULL, 1, N'["x","y"]', N'{"k":1}', N'{"n":2}', N'on', 1.5)`, q.Interpolate(WithDialect(SQLServer)))
+		require.Equal(t, `INSERT INTO t (a, b, c, d, e, f, g, h, i, j) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`, q.SQL())
+	})
+
+	t.Run("case=unbound placeholders keep their position", func(t *testing.T) {
+		q, err := ParseSelect(`SELECT id FROM users WHERE name = 'a\b' AND id = $1 AND role IN ($2, $3)`)
+		require.NoError(t, err)
+		require.Equal(t, InterpolatedPrefix+`SELECT id FROM users WHERE name = 'a\\b' AND id = ? AND role IN (?, ?)`, q.Interpolate(WithDialect(MySQL)))
+
+		q, err = ParseSelect(`SELECT id FROM users WHERE id = $1 AND name = $2`)
+		require.NoError(t, err)
+		require.Equal(t, InterpolatedPrefix+`SELECT id FROM users WHERE id = $1 AND name = $2`, q.Interpolate())
+	})
+
+	t.Run("case=redaction", func(t *testing.T) {
+		type user struct {
+			ID       int     `db:"id,pk"`
+			Email    string  `db:"email"`
+			Password string  `db:"password"`
+			Token    *string `db:"token"`
+		}
+		q := UpdateStruct("users", user{ID: 1, Email: "a@example.com", Password: "hunter2"})
+		require.Equal(t, InterpolatedPrefix+`UPDATE users SET email = 'a@example.com', password = '[redacted]', token = NULL WHERE id = 1`,
+			q.Interpolate(WithRedactor(RedactColumns("password", "token"))))
+		require.Equal(t, InterpolatedPrefix+"UPDATE users\nSET email = '[redacted]', password = '[redacted]', token = NULL\nWHERE id = 1",
+			Interpolate(q.Clone(), WithFormat(DefaultFormat), WithRedactor(func(column string, value any) any {
+				if column == "id" {
+					return value
+				}
+				return RedactColumns(column)(column, value)
+			})))
+	})
+}
@@ -72,25 +72,86 @@ func (j *Join) On(table string, joinColumn string) SelectFromQuery {
 	return j.parent
 }
 
+// Filter adds a condition on a bound value to the ON condition of the join,
+// e.g. for a Hook restricting the rows of the joined table. It has no effect
+// on a join without an ON condition, which Build rejects.
+func (j *Join) Filter(column string, operator Operator, values ...any) {
+	if j.on == nil {
+		return
+	}
+	c := &WhereCondition{ColumnA: column, Op: operator, values: values}
+	last := j.on
+	for last.next != nil {
+		last = last.next
+	}
+	last.nextOp, last.next = And, c
+}
+
+// GetTable returns the joined table.
+func (j *Join) GetTable() string {
+	return j.table
+}
+
+// GetAlias returns the alias of the joined table.
+func (j *Join) GetAlias() string {
+	return j.as
+}
+
+// GetType returns the type of the join.
+func (j *Join) GetType() JoinType {
+	return j.join
+}
+
+func (j *Join) clone(parent SelectFromQuery) *Join {
+	n := *j
+	n.on = j.on.Clone()
+	n.parent = parent
+	return &n
+}
+
 func (j *Join) SQL() string {
-	var sb strings.Builder
-	sb.WriteString(" ")
-	sb.WriteString(string(j.join))
+	rc := newRenderContext()
+	j.render(rc, 0)
+	return rc.sb.String()
+}
+
+// width returns the length of the join up to its ON condition.
+func (j *Join) width() int {
+	n := len(j.join) + 1 + len(j.table)
+	if j.as != "" {
+		n += len(" AS ") + len(j.as)
+	}
+	return n
+}
+
+// render writes the join, padded to width before its ON condition when
+// formatting.
+func (j *Join) render(rc *renderContext, width int) {
+	sb := &rc.sb
+	rc.newline(rc.indent(), " ")
+	rc.keyword(string(j.join))
 	sb.WriteString(" ")
 	sb.WriteString(j.table)
 	if j.as != "" {
-		sb.WriteString(" AS ")
+		sb.WriteString(" ")
+		rc.keyword("AS")
+		sb.WriteString(" ")
 		sb.WriteString(j.as)
 	}
-	sb.WriteString(" ON ")
-	sb.WriteString(j.on.ColumnA)
-	sb.WriteString(" ")
-
-	switch o := any(j.on.Op.get()).(type) {
-	case BasicOperator:
-		sb.WriteString(string(o))
+	if j.on == nil {
+		return
+	}
+	if rc.format != nil {
+		sb.WriteString(strings.Repeat(" ", max(0, width-j.width())))
 	}
 	sb.WriteString(" ")
-	sb.WriteString(string(j.on.ColumnB))
-	return sb.String()
+	rc.keyword("ON")
+	for c := j.on; c != nil; c = c.next {
+		sb.WriteString(" ")
+		conditionSQL(rc, c)
+		if c.next != nil {
+			sb.WriteString(" ")
+			rc.keyword(string(c.nextOp))
+		}
+	}
 }
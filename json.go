@@ -0,0 +1,305 @@
+package sqlbuilder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// SelectJSONVersion is the version of the JSON document a select is
+// marshalled to:
+//
+//	{
+//	  "version": 1,
+//	  "table": "users",
+//	  "alias": "u",
+//	  "columns": ["u.id", "u.name"],
+//	  "joins": [{
+//	    "type": "LEFT JOIN", "table": "roles", "alias": "r",
+//	    "on": [{"column": "r.id", "op": "=", "compare": "u.role_id"}]
+//	  }],
+//	  "where": [
+//	    {"column": "u.status", "op": "IN", "count": 2, "values": ["active", "invited"]},
+//	    {"logic": "OR", "group": [
+//	      {"column": "u.created_at", "op": ">="},
+//	      {"logic": "AND", "column": "u.deleted_at", "op": "IS NULL"}
+//	    ]}
+//	  ],
+//	  "order_by": [{"columns": ["u.created_at"], "direction": "DESC"}]
+//	}
+//
+// A condition is compared either with the column in "compare", with its
+// bound "values" or with the Param named by "param", or is left unbound when
+// it has none of them. "logic" joins a
+// condition to the one before it, "group" holds conditions rendered in
+// parentheses. The optional "qualify", "deleted" ("with" or "only") and
+// "tags" keep the state set by SelectFor, WithDeleted, OnlyDeleted and Tag.
+//
+// Bound values come back as the JSON types, e.g. float64 for numbers.
+const SelectJSONVersion = 1
+
+type (
+	selectJSON struct {
+		Version int             `json:"version"`
+		Table   string          `json:"table"`
+		Alias   string          `json:"alias,omitempty"`
+		Columns []string        `json:"columns,omitempty"`
+		Qualify bool            `json:"qualify,omitempty"`
+		Joins   []joinJSON      `json:"joins,omitempty"`
+		Where   []conditionJSON `json:"where,omitempty"`
+		OrderBy []sortJSON      `json:"order_by,omitempty"`
+		Deleted string          `json:"deleted,omitempty"`
+		Tags    Tags            `json:"tags,omitempty"`
+	}
+	joinJSON struct {
+		Type  JoinType        `json:"type"`
+		Table string          `json:"table"`
+		Alias string          `json:"alias,omitempty"`
+		On    []conditionJSON `json:"on"`
+	}
+	conditionJSON struct {
+		Logic   LogicalOperator `json:"logic,omitempty"`
+		Column  string          `json:"column,omitempty"`
+		Op      string          `json:"op,omitempty"`
+		Count   int             `json:"count,omitempty"`
+		Compare string          `json:"compare,omitempty"`
+		Values  []any           `json:"values,omitempty"`
+		Param   string          `json:"param,omitempty"`
+		Group   []conditionJSON `json:"group,omitempty"`
+	}
+	sortJSON struct {
+		Columns   []string `json:"columns"`
+		Direction OrderBy  `json:"direction"`
+	}
+)
+
+var deletedJSON = map[deletedRows]string{
+	withDeleted: "with",
+	onlyDeleted: "only",
+}
+
+// MarshalJSON writes the select as a document of version
+// SelectJSONVersion. It fails for a select which can't be built and for
+// operators other than those of this package.
+func (s *SelectBuilder) MarshalJSON() ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	doc := selectJSON{
+		Version: SelectJSONVersion,
+		Table:   s.table,
+		Alias:   s.alias,
+		Columns: s.columns,
+		Qualify: s.qualify,
+		Deleted: deletedJSON[s.deleted],
+		Tags:    s.tags,
+	}
+	for _, j := range s.joins {
+		on, err := conditionsJSON(j.on)
+		if err != nil {
+			return nil, err
+		}
+		doc.Joins = append(doc.Joins, joinJSON{Type: j.join, Table: j.table, Alias: j.as, On: on})
+	}
+	var err error
+	if doc.Where, err = conditionsJSON(s.GetWhere()); err != nil {
+		return nil, err
+	}
+	for o := s.orderBy; o != nil; o = o.next {
+		doc.OrderBy = append(doc.OrderBy, sortJSON{Columns: o.columns, Direction: o.orderBy})
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the select with the one of the document. Every
+// error wraps ErrInvalidDocument, documents of another version than
+// SelectJSONVersion also ErrUnsupportedVersion and unknown operators also
+// ErrUnknownOperator. Unknown keys are rejected.
+func (s *SelectBuilder) UnmarshalJSON(data []byte) error {
+	var doc selectJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	if doc.Version != SelectJSONVersion {
+		return fmt.Errorf("%w: %w %d", ErrInvalidDocument, ErrUnsupportedVersion, doc.Version)
+	}
+
+	n := SelectBuilder{
+		table:   doc.Table,
+		alias:   doc.Alias,
+		columns: doc.Columns,
+		qualify: doc.Qualify,
+		tags:    doc.Tags,
+	}
+	switch doc.Deleted {
+	case "":
+	case "with":
+		n.deleted = withDeleted
+	case "only":
+		n.deleted = onlyDeleted
+	default:
+		return fmt.Errorf("%w: invalid deleted %q", ErrInvalidDocument, doc.Deleted)
+	}
+	for _, j := range doc.Joins {
+		if !slices.Contains([]JoinType{InnerJoin, LeftJoin, RightJoin, FullOuterJoin}, j.Type) {
+			return fmt.Errorf("%w: invalid join type %q", ErrInvalidDocument, j.Type)
+		}
+		on, err := conditionsFromJSON(j.On)
+		if err != nil {
+			return err
+		}
+		n.joins = append(n.joins, &Join{join: j.Type, table: j.Table, as: j.Alias, on: on, parent: s})
+	}
+	where, err := conditionsFromJSON(doc.Where)
+	if err != nil {
+		return err
+	}
+	if where != nil {
+		n.WhereBuilder = &WhereBuilder[SelectFromQuery]{parent: s, where: where}
+	}
+	last := &n.orderBy
+	for _, o := range doc.OrderBy {
+		if o.Direction != Asc && o.Direction != Desc {
+			return fmt.Errorf("%w: invalid sort direction %q", ErrInvalidDocument, o.Direction)
+		}
+		*last = &Sort{columns: o.Columns, orderBy: o.Direction}
+		last = &(*last).next
+	}
+	*s = n
+	return nil
+}
+
+// UnmarshalSelect returns the select of a document written by
+// SelectBuilder.MarshalJSON.
+func UnmarshalSelect(data []byte) (SelectFromQuery, error) {
+	s := &SelectBuilder{}
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func conditionsJSON(c *WhereCondition) ([]conditionJSON, error) {
+	var conds []conditionJSON
+	var logic LogicalOperator
+	for ; c != nil; logic, c = c.nextOp, c.next {
+		cj := conditionJSON{Logic: logic}
+		if c.group != nil {
+			var err error
+			if cj.Group, err = conditionsJSON(c.group); err != nil {
+				return nil, err
+			}
+			conds = append(conds, cj)
+			continue
+		}
+
+		cj.Column, cj.Compare, cj.Values = c.ColumnA, c.ColumnB, c.values
+		switch op := c.Op.get().(type) {
+		case BasicOperator:
+			if !knownOperator(op) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownOperator, op)
+			}
+			cj.Op = string(op)
+		case SpecialOperator:
+			count, o := op()
+			if o != "IN" && o != "NOT IN" {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownOperator, o)
+			}
+			cj.Op, cj.Count = o, count
+		}
+		for _, v := range c.values {
+			p, ok := v.(Param)
+			switch {
+			case ok && cj.Count == 0:
+				cj.Param, cj.Values = string(p), nil
+			case ok:
+				return nil, fmt.Errorf("%w: parameter %s in the values of %s", ErrUnsupportedStatement, string(p), c.ColumnA)
+			}
+		}
+		conds = append(conds, cj)
+	}
+	return conds, nil
+}
+
+func conditionsFromJSON(conds []conditionJSON) (*WhereCondition, error) {
+	var first, last *WhereCondition
+	for i, cj := range conds {
+		switch {
+		case i == 0 && cj.Logic != "":
+			return nil, fmt.Errorf("%w: invalid logic %q on first condition", ErrInvalidDocument, cj.Logic)
+		case i > 0 && cj.Logic != And && cj.Logic != Or:
+			return nil, fmt.Errorf("%w: invalid logic %q", ErrInvalidDocument, cj.Logic)
+		}
+
+		c := &WhereCondition{}
+		if cj.Group != nil {
+			if cj.Column != "" || cj.Op != "" || cj.Compare != "" || cj.Values != nil || cj.Param != "" || cj.Count != 0 {
+				return nil, fmt.Errorf("%w: group with a comparison", ErrInvalidDocument)
+			}
+			var err error
+			if c.group, err = conditionsFromJSON(cj.Group); err != nil {
+				return nil, err
+			}
+			if c.group == nil {
+				return nil, fmt.Errorf("%w: empty group", ErrInvalidDocument)
+			}
+		} else {
+			if cj.Column == "" {
+				return nil, fmt.Errorf("%w: condition without column", ErrInvalidDocument)
+			}
+			var err error
+			if c.Op, err = operatorFromJSON(cj); err != nil {
+				return nil, err
+			}
+			c.ColumnA, c.ColumnB, c.values = cj.Column, cj.Compare, cj.Values
+			if cj.Param != "" {
+				c.values = []any{Param(cj.Param)}
+			}
+		}
+
+		if first == nil {
+			first = c
+		} else {
+			last.nextOp, last.next = cj.Logic, c
+		}
+		last = c
+	}
+	return first, nil
+}
+
+func operatorFromJSON(cj conditionJSON) (Operator, error) {
+	switch cj.Op {
+	case "IN", "NOT IN":
+		if cj.Count < 1 || cj.Values != nil && len(cj.Values) != cj.Count || cj.Param != "" {
+			return nil, fmt.Errorf("%w: %s on %s with count %d and %d values", ErrInvalidDocument, cj.Op, cj.Column, cj.Count, len(cj.Values))
+		}
+		if cj.Op == "IN" {
+			return In(cj.Count), nil
+		}
+		return NotIn(cj.Count), nil
+	}
+
+	op := BasicOperator(cj.Op)
+	switch {
+	case !knownOperator(op):
+		return nil, fmt.Errorf("%w: %w %q", ErrInvalidDocument, ErrUnknownOperator, cj.Op)
+	case cj.Count != 0:
+		return nil, fmt.Errorf("%w: %s on %s with a count", ErrInvalidDocument, cj.Op, cj.Column)
+	case op.unary() && (cj.Values != nil || cj.Compare != "" || cj.Param != ""),
+		cj.Compare != "" && cj.Values != nil,
+		cj.Param != "" && (cj.Values != nil || cj.Compare != ""),
+		len(cj.Values) > 1:
+		return nil, fmt.Errorf("%w: %s on %s with %d values", ErrInvalidDocument, cj.Op, cj.Column, len(cj.Values))
+	}
+	return op, nil
+}
+
+// knownOperator reports whether op is one of the operators of this package.
+func knownOperator(op BasicOperator) bool {
+	return slices.Contains(unaryOperators, op) || slices.Contains(wordOperators, op) ||
+		slices.Contains(slices.Collect(maps.Values(punctOperators)), op)
+}
@@ -0,0 +1,123 @@
+package sqlbuilder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectJSON(t *testing.T) {
+	t.Run("case=round trip", func(t *testing.T) {
+		q := Select("u.id", "u.name").From("users").As("u").
+			LeftJoin("roles").As("r").On("r.id", "u.role_id").
+			InnerJoin("teams").On("teams.id", "u.team_id").
+			Filter(Cond("u.status", In(2), "active", "invited"), AnyOf(Cond("u.created_at", GreaterThanOrEqual), Cond("u.deleted_at", IsNull))).
+			OrderBy(Desc, "u.created_at").ThenBy(Asc, "u.name").
+			OnlyDeleted()
+		q.Tag("report", "weekly")
+
+		data, err := json.Marshal(q)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"version": 1,
+			"table": "users",
+			"alias": "u",
+			"columns": ["u.id", "u.name"],
+			"joins": [
+				{"type": "LEFT JOIN", "table": "roles", "alias": "r", "on": [{"column": "r.id", "op": "=", "compare": "u.role_id"}]},
+				{"type": "INNER JOIN", "table": "teams", "on": [{"column": "teams.id", "op": "=", "compare": "u.team_id"}]}
+			],
+			"where": [
+				{"column": "u.status", "op": "IN", "count": 2, "values": ["active", "invited"]},
+				{"logic": "AND", "group": [
+					{"column": "u.created_at", "op": ">="},
+					{"logic": "OR", "column": "u.deleted_at", "op": "IS NULL"}
+				]}
+			],
+			"order_by": [{"columns": ["u.created_at"], "direction": "DESC"}, {"columns": ["u.name"], "direction": "ASC"}],
+			"deleted": "only",
+			"tags": {"report": "weekly"}
+		}`, string(data))
+
+		s, err := UnmarshalSelect(data)
+		require.NoError(t, err)
+		require.Equal(t, q.SQL(), s.SQL())
+		require.Equal(t, q.Args(), s.Args())
+
+		// The builder works as any other.
+		s = s.Where("id", Equals).Parent().InnerJoin("orgs").On("orgs.id", "u.org_id")
+		require.Equal(t, "SELECT u.id, u.name FROM users AS u LEFT JOIN roles AS r ON r.id = u.role_id "+
+			"INNER JOIN teams ON teams.id = u.team_id INNER JOIN orgs ON orgs.id = u.org_id WHERE id = $1 "+
+			"ORDER BY u.created_at DESC, u.name ASC /*report='weekly'*/", s.SQL())
+
+		var sb SelectBuilder
+		require.NoError(t, json.Unmarshal([]byte(`{"version": 1, "table": "users", "where": [{"column": "id", "op": "NOT IN", "count": 3}]}`), &sb))
+		require.Equal(t, "SELECT * FROM users WHERE id NOT IN ($1, $2, $3)", sb.SQL())
+	})
+
+	t.Run("case=params", func(t *testing.T) {
+		q := Select().From("posts").Filter(Cond("owner_id", Equals, Param("user_id")), Cond("status", Equals, "draft"))
+		b, err := json.Marshal(q)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"version": 1, "table": "posts", "where": [
+			{"column": "owner_id", "op": "=", "param": "user_id"},
+			{"logic": "AND", "column": "status", "op": "=", "values": ["draft"]}
+		]}`, string(b))
+
+		var sb SelectBuilder
+		require.NoError(t, json.Unmarshal(b, &sb))
+		args, err := Bind(&sb, map[string]any{"user_id": 5})
+		require.NoError(t, err)
+		require.Equal(t, []any{5, "draft"}, args)
+	})
+
+	t.Run("case=rejected", func(t *testing.T) {
+		for doc, expected := range map[string]error{
+			`{"version": 2, "table": "users"}`:                                                                                      ErrUnsupportedVersion,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "~"}]}`:                                               ErrUnknownOperator,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "BETWEEN"}]}`:                                         ErrUnknownOperator,
+			`{"version": 1, "table": "users", "joins": [{"type": "LEFT JOIN", "table": "r", "on": [{"column": "a", "op": "=~"}]}]}`: ErrUnknownOperator,
+		} {
+			_, err := UnmarshalSelect([]byte(doc))
+			require.ErrorIs(t, err, expected, doc)
+			require.ErrorIs(t, err, ErrInvalidDocument, doc)
+		}
+
+		for _, doc := range []string{
+			`{"version": 1, "table": "users", "limit": 1}`,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "IN"}]}`,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "IN", "count": 2, "values": [1]}]}`,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "IS NULL", "values": [1]}]}`,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "IS NULL", "param": "p"}]}`,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "=", "values": [1], "param": "p"}]}`,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "IN", "count": 1, "param": "p"}]}`,
+			`{"version": 1, "table": "users", "where": [{"logic": "AND", "column": "a", "op": "="}]}`,
+			`{"version": 1, "table": "users", "where": [{"column": "a", "op": "="}, {"logic": "XOR", "column": "b", "op": "="}]}`,
+			`{"version": 1, "table": "users", "where": [{"group": []}]}`,
+			`{"version": 1, "table": "users", "where": [{"op": "="}]}`,
+			`{"version": 1, "table": "users", "where": [{"group": [{"column": "a", "op": "="}], "column": "b"}]}`,
+			`{"version": 1, "table": "users", "deleted": "all"}`,
+			`{"version": 1, "table": "users"`,
+			`{"version": 1, "table": "users", "joins": [{"type": "CROSS JOIN", "table": "r"}]}`,
+			`{"version": 1, "table": "users", "order_by": [{"columns": ["a"], "direction": "UP"}]}`,
+		} {
+			_, err := UnmarshalSelect([]byte(doc))
+			require.ErrorIs(t, err, ErrInvalidDocument, doc)
+		}
+	})
+
+	t.Run("case=marshal errors", func(t *testing.T) {
+		between := SpecialOperatorFunc(func(count int) SpecialOperator {
+			return func() (int, string) { return count, "BETWEEN" }
+		})
+		_, err := json.Marshal(Select().From("users").Where("a", between(2)).Parent())
+		require.ErrorIs(t, err, ErrUnknownOperator)
+
+		_, err = json.Marshal(Select().From("users").Filter(Cond("a", In(2), 1, Param("b"))))
+		require.ErrorIs(t, err, ErrUnsupportedStatement)
+
+		_, err = json.Marshal(SelectFor[int]().From("users"))
+		require.ErrorIs(t, err, ErrInvalidStruct)
+	})
+}
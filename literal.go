@@ -0,0 +1,119 @@
+package sqlbuilder
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// literal renders v as an SQL literal of the dialect. It is used where a
+// statement can't take bound values, such as the predicate of an index, and
+// by Interpolate. Slices are arrays on Postgres and JSON text elsewhere, maps
+// and structs are JSON text.
+func (d *Dialect) literal(v any) string {
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "NULL"
+		}
+		v = value
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return d.quote(v)
+	case bool:
+		switch {
+		case d == SQLServer && v:
+			return "1"
+		case d == SQLServer:
+			return "0"
+		case v:
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return d.quote(v.Format("2006-01-02 15:04:05.999999Z07:00"))
+	case json.RawMessage:
+		return d.quote(string(v))
+	case []byte:
+		return d.bytes(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "NULL"
+		}
+		return d.literal(rv.Elem().Interface())
+	case reflect.Bool:
+		return d.literal(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return d.literal(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return d.literal(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return d.literal(rv.Float())
+	case reflect.String:
+		return d.quote(rv.String())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return "NULL"
+		}
+		if d == Postgres {
+			if rv.Len() == 0 {
+				return "'{}'"
+			}
+			elems := make([]string, rv.Len())
+			for i := range elems {
+				elems[i] = d.literal(rv.Index(i).Interface())
+			}
+			return "ARRAY[" + strings.Join(elems, ", ") + "]"
+		}
+		fallthrough
+	case reflect.Map, reflect.Struct:
+		if b, err := json.Marshal(v); err == nil {
+			return d.quote(string(b))
+		}
+	}
+	return d.quote(fmt.Sprint(v))
+}
+
+// bytes renders b as a binary literal of the dialect.
+func (d *Dialect) bytes(b []byte) string {
+	h := hex.EncodeToString(b)
+	switch d {
+	case MySQL, SQLite:
+		return "X'" + h + "'"
+	case SQLServer:
+		return "0x" + h
+	}
+	return `'\x` + h + `'::bytea`
+}
+
+// quote quotes s as a string literal of the dialect.
+func (d *Dialect) quote(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if d == MySQL {
+		// MySQL treats backslashes in string literals as escapes.
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	if d == SQLServer {
+		return "N'" + s + "'"
+	}
+	return "'" + s + "'"
+}
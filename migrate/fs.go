@@ -0,0 +1,85 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidFileName = errors.New("migrate: migration file name must be <version>_<name>.up.sql or <version>_<name>.down.sql")
+
+// noTransaction is the first line of a .sql file which must not run in a
+// transaction.
+const noTransaction = "-- migrate:no-transaction"
+
+// FromFS reads the migrations in dir of fsys, typically an embed.FS. Every
+// migration is a <version>_<name>.up.sql file with an optional
+// <version>_<name>.down.sql file, each run as a single statement.
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	m, err := migrate.FromFS(migrations, "migrations")
+func FromFS(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	var migrations []*Migration
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(e.Name(), ".sql")
+		base, up := strings.CutSuffix(base, ".up")
+		if !up {
+			var down bool
+			if base, down = strings.CutSuffix(base, ".down"); !down {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, e.Name())
+			}
+		}
+		v, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, e.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		query := strings.TrimSpace(string(content))
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+			migrations = append(migrations, m)
+		}
+		if up {
+			if len(m.Up) != 0 {
+				return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, version)
+			}
+			m.Up = []Statement{SQL(query)}
+			m.NoTransaction = strings.HasPrefix(query, noTransaction)
+		} else {
+			if len(m.Down) != 0 {
+				return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, version)
+			}
+			m.Down = []Statement{SQL(query)}
+		}
+	}
+
+	for _, m := range migrations {
+		if len(m.Up) == 0 {
+			return nil, fmt.Errorf("%w: %d %s has no up file", ErrInvalidFileName, m.Version, m.Name)
+		}
+	}
+	return migrations, nil
+}
@@ -0,0 +1,376 @@
+// Package migrate applies and reverts schema migrations built with
+// sqlbuilder statements or read from .sql files, tracking the applied
+// versions in a table of the database.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Benehiko/sqlbuilder"
+	"github.com/Benehiko/sqlbuilder/executor"
+)
+
+type (
+	// Statement is implemented by every sqlbuilder statement and by SQL.
+	Statement = executor.Statement
+
+	// Migration is a single version of the schema. Down may be empty for
+	// migrations which can't be reverted.
+	Migration struct {
+		Version int64
+		Name    string
+		Up      []Statement
+		Down    []Statement
+		// NoTransaction runs the migration outside of a transaction, e.g.
+		// for CREATE INDEX CONCURRENTLY.
+		NoTransaction bool
+	}
+
+	// Status is the state of a migration in the database.
+	Status struct {
+		Version   int64
+		Name      string
+		Applied   bool
+		AppliedAt time.Time
+	}
+
+	Migrator struct {
+		db         *sql.DB
+		dialect    *sqlbuilder.Dialect
+		table      string
+		dryRun     io.Writer
+		migrations []*Migration
+	}
+
+	Option func(m *Migrator)
+
+	rawStatement struct {
+		query string
+		args  []any
+	}
+
+	// applied is a row of the versions table.
+	applied struct {
+		Version   int64     `db:"version"`
+		Name      string    `db:"name"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+
+	// statements is implemented by statements which may need more than one
+	// statement to run, such as sqlbuilder.AlterTableBuilder.
+	statements interface {
+		Statements(opts ...sqlbuilder.RenderOption) ([]string, error)
+	}
+)
+
+// DefaultTable is the name of the versions table unless WithTable is used.
+const DefaultTable = "schema_migrations"
+
+var (
+	ErrDuplicateVersion = errors.New("migrate: duplicate migration version")
+	ErrIrreversible     = errors.New("migrate: migration has no down statements")
+)
+
+// SQL is a raw statement, written for the dialect of the Migrator.
+func SQL(query string, args ...any) Statement {
+	return rawStatement{query: query, args: args}
+}
+
+func (s rawStatement) Build(...sqlbuilder.RenderOption) (string, error) {
+	return s.query, nil
+}
+
+func (s rawStatement) Args() []any {
+	return s.args
+}
+
+// WithDialect builds the statements for d instead of Postgres.
+func WithDialect(d *sqlbuilder.Dialect) Option {
+	return func(m *Migrator) {
+		m.dialect = d
+	}
+}
+
+// WithTable tracks the applied versions in table instead of DefaultTable.
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// DryRun writes the statements Up and Down would run to w instead of
+// running them. Nothing is written to the database.
+func DryRun(w io.Writer) Option {
+	return func(m *Migrator) {
+		m.dryRun = w
+	}
+}
+
+// New returns a Migrator for the given migrations, which may be passed in
+// any order.
+func New(db *sql.DB, migrations []*Migration, opts ...Option) (*Migrator, error) {
+	m := &Migrator{
+		db:         db,
+		dialect:    sqlbuilder.Postgres,
+		table:      DefaultTable,
+		migrations: slices.Clone(migrations),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	slices.SortFunc(m.migrations, func(a, b *Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for i := 1; i < len(m.migrations); i++ {
+		if m.migrations[i].Version == m.migrations[i-1].Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, m.migrations[i].Version)
+		}
+	}
+	return m, nil
+}
+
+// Up applies every pending migration in order and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	var done []*Migration
+	err := m.locked(ctx, func(conn *sql.Conn, versions map[int64]applied) error {
+		for _, mig := range m.migrations {
+			if _, ok := versions[mig.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, mig, mig.Up, true); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the last applied migration and returns it, or nil when no
+// migration is applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var done *Migration
+	err := m.locked(ctx, func(conn *sql.Conn, versions map[int64]applied) error {
+		for _, mig := range slices.Backward(m.migrations) {
+			if _, ok := versions[mig.Version]; !ok {
+				continue
+			}
+			if len(mig.Down) == 0 {
+				return fmt.Errorf("%w: %d %s", ErrIrreversible, mig.Version, mig.Name)
+			}
+			done = mig
+			return m.run(ctx, conn, mig, mig.Down, false)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status reports every known migration and whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	versions, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		a, ok := versions[mig.Version]
+		status[i] = Status{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Applied:   ok,
+			AppliedAt: a.AppliedAt,
+		}
+	}
+	return status, nil
+}
+
+// locked runs f on a single connection holding the migration lock of the
+// dialect, so concurrent migrators wait for each other.
+func (m *Migrator) locked(ctx context.Context, f func(conn *sql.Conn, versions map[int64]applied) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	lock, unlock, key := m.lockStatements()
+	if lock != "" && m.dryRun == nil {
+		if _, err := conn.ExecContext(ctx, lock, key); err != nil {
+			return fmt.Errorf("migrate: acquiring lock: %w", err)
+		}
+		defer func() {
+			if _, uerr := conn.ExecContext(context.WithoutCancel(ctx), unlock, key); uerr != nil {
+				err = errors.Join(err, fmt.Errorf("migrate: releasing lock: %w", uerr))
+			}
+		}()
+	}
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return err
+	}
+	versions, err := m.applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return f(conn, versions)
+}
+
+// lockStatements returns the statements taking and releasing an advisory
+// lock, or nothing when the dialect has no such lock.
+func (m *Migrator) lockStatements() (lock, unlock string, key any) {
+	switch m.dialect {
+	case sqlbuilder.Postgres:
+		h := fnv.New64a()
+		h.Write([]byte(m.table))
+		return "SELECT pg_advisory_lock($1)", "SELECT pg_advisory_unlock($1)", int64(h.Sum64())
+	case sqlbuilder.MySQL:
+		return "SELECT GET_LOCK(?, -1)", "SELECT RELEASE_LOCK(?)", m.table
+	case sqlbuilder.SQLServer:
+		return "EXEC sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session'",
+			"EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", m.table
+	}
+	return "", "", nil
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	create := sqlbuilder.CreateTable(m.table).
+		Columns(
+			sqlbuilder.Column("version", sqlbuilder.BigInt).PrimaryKey(),
+			sqlbuilder.Column("name", sqlbuilder.Varchar(255)).NotNull(),
+			sqlbuilder.Column("applied_at", sqlbuilder.Timestamp).NotNull(),
+		)
+	if m.dialect != sqlbuilder.SQLServer {
+		create.IfNotExists()
+	}
+	query, err := create.Build(sqlbuilder.WithDialect(m.dialect))
+	if err != nil {
+		return err
+	}
+	if m.dialect == sqlbuilder.SQLServer {
+		query = fmt.Sprintf("IF OBJECT_ID(N'%s', N'U') IS NULL %s", m.table, query)
+	}
+
+	if m.dryRun != nil {
+		_, err = fmt.Fprintf(m.dryRun, "%s;\n", query)
+		return err
+	}
+	_, err = conn.ExecContext(ctx, query)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]applied, error) {
+	q := sqlbuilder.Select("version", "name", "applied_at").From(m.table).OrderBy(sqlbuilder.Asc, "version")
+	rows, err := executor.All[applied](ctx, executor.New(conn, sqlbuilder.WithDialect(m.dialect)), q)
+	if err != nil && m.dryRun == nil {
+		return nil, err
+	}
+
+	// A dry run may be the first one, before the versions table exists.
+	versions := make(map[int64]applied, len(rows))
+	for _, r := range rows {
+		versions[r.Version] = r
+	}
+	return versions, nil
+}
+
+// run runs the statements of the migration and records it as applied when
+// up is set or as reverted otherwise.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, mig *Migration, stmts []Statement, up bool) (err error) {
+	var record Statement
+	var args []any
+	if up {
+		record = sqlbuilder.Insert("version", "name", "applied_at").Into(m.table).
+			Values(mig.Version, mig.Name, time.Now().UTC())
+	} else {
+		record = sqlbuilder.Delete().From(m.table).Where("version", sqlbuilder.Equals)
+		args = []any{mig.Version}
+	}
+
+	if m.dryRun != nil {
+		direction := "down"
+		if up {
+			direction = "up"
+		}
+		if _, err := fmt.Fprintf(m.dryRun, "-- %d %s (%s)\n", mig.Version, mig.Name, direction); err != nil {
+			return err
+		}
+	}
+
+	var q executor.Querier = conn
+	if !mig.NoTransaction && m.dryRun == nil {
+		var tx *sql.Tx
+		if tx, err = conn.BeginTx(ctx, nil); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				err = errors.Join(err, tx.Rollback())
+				return
+			}
+			err = tx.Commit()
+		}()
+		q = tx
+	}
+
+	for _, stmt := range stmts {
+		queries, err := m.build(stmt)
+		if err != nil {
+			return fmt.Errorf("migrate: %d %s: %w", mig.Version, mig.Name, err)
+		}
+		for _, query := range queries {
+			if err := m.exec(ctx, q, query, stmt.Args()); err != nil {
+				return fmt.Errorf("migrate: %d %s: %w", mig.Version, mig.Name, err)
+			}
+		}
+	}
+
+	query, err := record.Build(sqlbuilder.WithDialect(m.dialect))
+	if err != nil {
+		return err
+	}
+	if m.dryRun != nil {
+		return nil
+	}
+	return m.exec(ctx, q, query, append(record.Args(), args...))
+}
+
+func (m *Migrator) build(stmt Statement) ([]string, error) {
+	if s, ok := stmt.(statements); ok {
+		return s.Statements(sqlbuilder.WithDialect(m.dialect))
+	}
+	query, err := stmt.Build(sqlbuilder.WithDialect(m.dialect))
+	if err != nil {
+		return nil, err
+	}
+	return []string{query}, nil
+}
+
+func (m *Migrator) exec(ctx context.Context, q executor.Querier, query string, args []any) error {
+	if m.dryRun != nil {
+		_, err := fmt.Fprintf(m.dryRun, "%s;\n", query)
+		return err
+	}
+	_, err := q.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,252 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"embed"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Benehiko/sqlbuilder"
+	"github.com/Benehiko/sqlbuilder/internal/fakedb"
+)
+
+//go:embed testdata/migrations/*.sql
+var testdata embed.FS
+
+const createVersions = "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, " +
+	"name VARCHAR(255) NOT NULL, applied_at TIMESTAMPTZ NOT NULL)"
+
+func migrations() []*Migration {
+	return []*Migration{
+		{
+			Version: 2,
+			Name:    "add nickname",
+			Up:      []Statement{sqlbuilder.AlterTable("users").AddColumn(sqlbuilder.Column("nickname", sqlbuilder.Text))},
+			Down:    []Statement{sqlbuilder.AlterTable("users").DropColumn("nickname")},
+		},
+		{
+			Version: 1,
+			Name:    "create users",
+			Up: []Statement{sqlbuilder.CreateTable("users").Columns(
+				sqlbuilder.Column("id", sqlbuilder.BigInt).Identity().PrimaryKey(),
+			)},
+			Down: []Statement{sqlbuilder.DropTable("users")},
+		},
+	}
+}
+
+// versions answers the query of the applied versions with the given ones.
+func versions(applied ...int64) fakedb.Handler {
+	return func(query string, _ []any) fakedb.Result {
+		if !strings.HasPrefix(query, "SELECT version") {
+			return fakedb.Result{}
+		}
+		res := fakedb.Result{Columns: []string{"version", "name", "applied_at"}}
+		for _, v := range applied {
+			res.Rows = append(res.Rows, []driver.Value{v, "applied", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)})
+		}
+		return res
+	}
+}
+
+func TestUp(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("case=applies pending migrations in order", func(t *testing.T) {
+		db, fake := fakedb.Open(versions())
+		m, err := New(db, migrations())
+		require.NoError(t, err)
+
+		done, err := m.Up(ctx)
+		require.NoError(t, err)
+		require.Len(t, done, 2)
+
+		require.Equal(t, []string{
+			"SELECT pg_advisory_lock($1)",
+			createVersions,
+			"SELECT version, name, applied_at FROM schema_migrations ORDER BY version ASC",
+			"BEGIN",
+			"CREATE TABLE users (id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY)",
+			"INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+			"COMMIT",
+			"BEGIN",
+			"ALTER TABLE users ADD COLUMN nickname TEXT",
+			"INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+			"COMMIT",
+			"SELECT pg_advisory_unlock($1)",
+		}, fake.Queries())
+
+		calls := fake.Calls()
+		require.Equal(t, calls[0].Args, calls[len(calls)-1].Args)
+		require.Equal(t, []any{int64(1), "create users"}, calls[5].Args[:2])
+	})
+
+	t.Run("case=skips applied migrations", func(t *testing.T) {
+		db, fake := fakedb.Open(versions(1))
+		m, err := New(db, migrations())
+		require.NoError(t, err)
+
+		done, err := m.Up(ctx)
+		require.NoError(t, err)
+		require.Len(t, done, 1)
+		require.EqualValues(t, 2, done[0].Version)
+		require.NotContains(t, fake.Queries(), "CREATE TABLE users (id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY)")
+	})
+
+	t.Run("case=rolls back a failed migration", func(t *testing.T) {
+		failure := errors.New("boom")
+		db, fake := fakedb.Open(func(query string, args []any) fakedb.Result {
+			if strings.HasPrefix(query, "ALTER TABLE") {
+				return fakedb.Result{Err: failure}
+			}
+			return versions()(query, args)
+		})
+		m, err := New(db, migrations())
+		require.NoError(t, err)
+
+		done, err := m.Up(ctx)
+		require.ErrorIs(t, err, failure)
+		require.Len(t, done, 1)
+
+		queries := fake.Queries()
+		require.Equal(t, []string{"ROLLBACK", "SELECT pg_advisory_unlock($1)"}, queries[len(queries)-2:])
+	})
+
+	t.Run("case=splits statements for sqlite without locking", func(t *testing.T) {
+		db, fake := fakedb.Open(versions(1))
+		m, err := New(db, []*Migration{{
+			Version: 1,
+			Name:    "create users",
+		}, {
+			Version: 2,
+			Name:    "rename",
+			Up:      []Statement{sqlbuilder.AlterTable("users").DropColumn("a").DropColumn("b")},
+		}}, WithDialect(sqlbuilder.SQLite), WithTable("versions"))
+		require.NoError(t, err)
+
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS versions (version INTEGER PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME NOT NULL)",
+			"SELECT version, name, applied_at FROM versions ORDER BY version ASC",
+			"BEGIN",
+			"ALTER TABLE users DROP COLUMN a",
+			"ALTER TABLE users DROP COLUMN b",
+			"INSERT INTO versions (version, name, applied_at) VALUES (?, ?, ?)",
+			"COMMIT",
+		}, fake.Queries())
+	})
+
+	t.Run("case=dry run", func(t *testing.T) {
+		db, fake := fakedb.Open(versions(1))
+		var out bytes.Buffer
+		m, err := New(db, migrations(), DryRun(&out))
+		require.NoError(t, err)
+
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+		require.Equal(t, createVersions+";\n"+
+			"-- 2 add nickname (up)\n"+
+			"ALTER TABLE users ADD COLUMN nickname TEXT;\n", out.String())
+		require.Equal(t, []string{"SELECT version, name, applied_at FROM schema_migrations ORDER BY version ASC"}, fake.Queries())
+	})
+}
+
+func TestDown(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("case=reverts the last migration", func(t *testing.T) {
+		db, fake := fakedb.Open(versions(1, 2))
+		m, err := New(db, migrations())
+		require.NoError(t, err)
+
+		done, err := m.Down(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, done.Version)
+
+		calls := fake.Calls()
+		require.Equal(t, "ALTER TABLE users DROP COLUMN nickname", calls[4].Query)
+		require.Equal(t, "DELETE FROM schema_migrations WHERE version = $1", calls[5].Query)
+		require.Equal(t, []any{int64(2)}, calls[5].Args)
+		require.Equal(t, "COMMIT", calls[6].Query)
+	})
+
+	t.Run("case=nothing applied", func(t *testing.T) {
+		db, _ := fakedb.Open(versions())
+		m, err := New(db, migrations())
+		require.NoError(t, err)
+
+		done, err := m.Down(ctx)
+		require.NoError(t, err)
+		require.Nil(t, done)
+	})
+
+	t.Run("case=irreversible", func(t *testing.T) {
+		db, _ := fakedb.Open(versions(1))
+		m, err := New(db, []*Migration{{Version: 1, Name: "create users", Up: []Statement{SQL("SELECT 1")}}})
+		require.NoError(t, err)
+
+		_, err = m.Down(ctx)
+		require.ErrorIs(t, err, ErrIrreversible)
+	})
+}
+
+func TestStatus(t *testing.T) {
+	db, _ := fakedb.Open(versions(1))
+	m, err := New(db, migrations())
+	require.NoError(t, err)
+
+	status, err := m.Status(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []Status{
+		{Version: 1, Name: "create users", Applied: true, AppliedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Version: 2, Name: "add nickname"},
+	}, status)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, []*Migration{{Version: 1}, {Version: 1}})
+	require.ErrorIs(t, err, ErrDuplicateVersion)
+}
+
+func TestFromFS(t *testing.T) {
+	t.Run("case=embed", func(t *testing.T) {
+		migrations, err := FromFS(testdata, "testdata/migrations")
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+
+		require.EqualValues(t, 1, migrations[0].Version)
+		require.Equal(t, "create_users", migrations[0].Name)
+		require.Len(t, migrations[0].Down, 1)
+		require.False(t, migrations[0].NoTransaction)
+
+		require.True(t, migrations[1].NoTransaction)
+		require.Empty(t, migrations[1].Down)
+
+		db, fake := fakedb.Open(versions(1))
+		m, err := New(db, migrations)
+		require.NoError(t, err)
+		_, err = m.Up(context.Background())
+		require.NoError(t, err)
+		require.NotContains(t, fake.Queries(), "BEGIN")
+		require.Contains(t, fake.Queries(), "-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY users_email_idx ON users (email)")
+	})
+
+	t.Run("case=invalid", func(t *testing.T) {
+		for _, files := range []fstest.MapFS{
+			{"m/create_users.up.sql": {}},
+			{"m/1_create_users.sql": {}},
+			{"m/1_create_users.down.sql": {}},
+		} {
+			_, err := FromFS(files, "m")
+			require.ErrorIs(t, err, ErrInvalidFileName)
+		}
+	})
+}
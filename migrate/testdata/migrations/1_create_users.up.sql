@@ -0,0 +1 @@
+CREATE TABLE users (id BIGINT PRIMARY KEY, email TEXT NOT NULL)
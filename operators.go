@@ -52,3 +52,12 @@ func (b BasicOperator) get() any {
 func (s SpecialOperator) get() any {
 	return s
 }
+
+// unary reports whether the operator takes no value, e.g. IS NULL.
+func (b BasicOperator) unary() bool {
+	switch b {
+	case IsNull, IsNotNull, IsTrue, IsNotTrue, IsFalse, IsNotFalse:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package sqlbuilder
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/Benehiko/sqlbuilder/internal/fields"
+)
+
+// Param is a named parameter, usable in place of any bound value, e.g.
+//
+//	Cond("owner_id", Equals, Param("user_id"))
+//	Insert("name", "created_by").Into("posts").Values(Param("name"), Param("user_id"))
+//
+// Bind and BindStruct return the arguments of a statement with the values
+// of its parameters. A parameter used more than once is bound once: Postgres
+// repeats its placeholder and SQL Server renders it as @name, bound with
+// sql.Named. Other dialects repeat the value for each ? placeholder.
+type Param string
+
+// Value implements driver.Valuer and always fails with ErrMissingParam, so a
+// statement run with its parameters unbound errors instead of binding their
+// names.
+func (p Param) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("%w: %s", ErrMissingParam, string(p))
+}
+
+// Bind returns the arguments of the statement rendered with opts, in
+// placeholder order, with the value of params for each Param. It fails with
+// ErrMissingParam when params has no value for a Param of the statement.
+func Bind(q Statement, params map[string]any, opts ...RenderOption) ([]any, error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	var args []any
+	opts = append(opts, CaptureArgs(&args), func(rc *renderContext) {
+		rc.params = params
+	})
+	if _, err := q.Build(opts...); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// BindStruct is Bind with the values of the parameters taken from the fields
+// of the struct v, named by their `db` tags.
+func BindStruct(q Statement, v any, opts ...RenderOption) ([]any, error) {
+	t, rows, err := structRows(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("%w: got %T", ErrInvalidStruct, v)
+	}
+	params := map[string]any{}
+	for _, f := range fields.Of(t) {
+		params[f.Column], _ = f.Value(rows[0])
+	}
+	return Bind(q, params, opts...)
+}
+
+// param writes the placeholder of the named parameter and records its
+// value, the parameter itself unless rendering for Bind.
+func (rc *renderContext) param(name string) {
+	var v any = Param(name)
+	if rc.params != nil {
+		var ok bool
+		if v, ok = rc.params[name]; !ok && rc.err == nil {
+			rc.err = fmt.Errorf("%w: %s", ErrMissingParam, name)
+		}
+	}
+	if rc.named == nil {
+		rc.named = map[string]int{}
+	}
+	pos, seen := rc.named[name]
+
+	switch {
+	case rc.dialect.named != nil:
+		rc.sb.WriteString(rc.dialect.named(name))
+		if !seen {
+			rc.named[name] = 0
+			rc.args = append(rc.args, sql.Named(name, v))
+		}
+	case rc.dialect.reuse && seen:
+		rc.sb.WriteString(rc.dialect.placeholder(pos))
+	default:
+		rc.named[name] = rc.pos
+		rc.args = append(rc.args, v)
+		rc.placeholder()
+	}
+}
@@ -0,0 +1,85 @@
+package sqlbuilder
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams(t *testing.T) {
+	q := Select().From("posts").
+		Where("published", IsTrue).And("title", Like).Parent().
+		Filter(Cond("org_id", Equals, Param("org_id")), AnyOf(Cond("author_id", Equals, Param("user_id")), Cond("editor_id", Equals, Param("user_id"))))
+
+	t.Run("case=postgres", func(t *testing.T) {
+		require.Equal(t, "SELECT * FROM posts WHERE org_id = $1 AND (author_id = $2 OR editor_id = $2) "+
+			"AND published IS TRUE AND title LIKE $3", q.SQL())
+		require.Equal(t, []any{Param("org_id"), Param("user_id")}, q.Args())
+
+		args, err := Bind(q, map[string]any{"user_id": 1, "org_id": 2}, WithArgs("a%"))
+		require.NoError(t, err)
+		require.Equal(t, []any{2, 1, "a%"}, args)
+	})
+
+	t.Run("case=mysql", func(t *testing.T) {
+		s, err := q.Build(WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM posts WHERE org_id = ? AND (author_id = ? OR editor_id = ?) AND published IS TRUE AND title LIKE ?", s)
+
+		args, err := Bind(q, map[string]any{"user_id": 1, "org_id": 2}, WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, []any{2, 1, 1}, args)
+	})
+
+	t.Run("case=sqlserver", func(t *testing.T) {
+		s, err := q.Build(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM posts WHERE org_id = @org_id AND (author_id = @user_id OR editor_id = @user_id) "+
+			"AND published IS TRUE AND title LIKE @p1", s)
+
+		args, err := Bind(q, map[string]any{"user_id": 1, "org_id": 2}, WithDialect(SQLServer), WithArgs("a%"))
+		require.NoError(t, err)
+		require.Equal(t, []any{sql.Named("org_id", 2), sql.Named("user_id", 1), "a%"}, args)
+	})
+
+	t.Run("case=bind struct", func(t *testing.T) {
+		type params struct {
+			UserID int    `db:"user_id"`
+			OrgID  int    `db:"org_id"`
+			Name   string `db:"name"`
+		}
+		i := Insert("name", "created_by").Into("posts").Values(Param("name"), Param("user_id")).Values("bar", Param("user_id"))
+		args, err := BindStruct(i, &params{UserID: 1, Name: "foo"})
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO posts (name, created_by) VALUES ($1, $2), ($3, $2)", i.SQL())
+		require.Equal(t, []any{"foo", 1, "bar"}, args)
+
+		_, err = BindStruct(q, []params{{}, {}})
+		require.ErrorIs(t, err, ErrInvalidStruct)
+	})
+
+	t.Run("case=missing", func(t *testing.T) {
+		_, err := Bind(q, map[string]any{"user_id": 1})
+		require.ErrorIs(t, err, ErrMissingParam)
+		_, err = Bind(q, nil)
+		require.ErrorIs(t, err, ErrMissingParam)
+	})
+
+	t.Run("case=unbound", func(t *testing.T) {
+		_, err := Param("user_id").Value()
+		require.ErrorIs(t, err, ErrMissingParam)
+	})
+
+	t.Run("case=inline", func(t *testing.T) {
+		_, err := CreateIndex("posts_owner_idx").On("posts", "title").Where("owner_id", Equals, Param("user_id")).Build()
+		require.ErrorIs(t, err, ErrUnsupportedStatement)
+	})
+
+	t.Run("case=debugging", func(t *testing.T) {
+		require.Equal(t, InterpolatedPrefix+"SELECT * FROM posts WHERE org_id = $1 AND (author_id = $2 OR editor_id = $2) "+
+			"AND published IS TRUE AND title LIKE $3", q.Interpolate())
+		require.Equal(t, "SELECT * FROM posts WHERE org_id = ? AND (author_id = ? OR editor_id = ?) AND published IS TRUE AND title LIKE ?",
+			q.Fingerprint().Normalized)
+	})
+}
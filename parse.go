@@ -0,0 +1,785 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type (
+	sqlTokenKind int
+	sqlToken     struct {
+		kind  sqlTokenKind
+		text  string
+		start int
+		end   int
+	}
+)
+
+const (
+	sqlIdent sqlTokenKind = iota
+	sqlString
+	sqlNumber
+	sqlPlaceholder
+	sqlPunct
+)
+
+// Parse reads a SELECT, INSERT, UPDATE or DELETE statement of the subset
+// the builders render into the matching builder, so it can be changed with
+// the builder methods and rendered again:
+//
+//	Parse(q.SQL()).SQL() == q.SQL()
+//
+// Literal values are turned into bound values, returned by Args, and can't
+// follow a placeholder. Placeholders must be numbered in order. Comments are
+// skipped, except a trailing comment in the sqlcommenter format written for
+// Tags, which become the tags of the statement.
+func Parse(query string) (Statement, error) {
+	p, err := newSQLParser(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var stmt Statement
+	switch {
+	case p.keyword("SELECT"):
+		stmt, err = p.selectStatement(nil)
+	case p.keyword("INSERT"):
+		stmt, err = p.insertStatement()
+	case p.keyword("UPDATE"):
+		stmt, err = p.updateStatement()
+	case p.keyword("DELETE"):
+		stmt, err = p.deleteStatement()
+	default:
+		return nil, p.errorf("expected SELECT, INSERT, UPDATE or DELETE")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.accept(";")
+	if !p.eof() {
+		return nil, p.errorf("unexpected token")
+	}
+	for _, k := range slices.Sorted(maps.Keys(p.tags)) {
+		stmt.Tag(k, p.tags[k])
+	}
+	return stmt, nil
+}
+
+// ParseSelect parses a SELECT statement, see Parse.
+func ParseSelect(query string) (SelectFromQuery, error) {
+	return parseAs[SelectFromQuery](query, "SELECT")
+}
+
+// ParseInsert parses an INSERT ... VALUES statement, see Parse. Use Parse
+// for INSERT ... SELECT statements.
+func ParseInsert(query string) (InsertIntoQuery, error) {
+	return parseAs[InsertIntoQuery](query, "INSERT ... VALUES")
+}
+
+// ParseUpdate parses an UPDATE statement, see Parse.
+func ParseUpdate(query string) (UpdateWhereQuery, error) {
+	return parseAs[UpdateWhereQuery](query, "UPDATE")
+}
+
+// ParseDelete parses a DELETE statement, see Parse.
+func ParseDelete(query string) (DeleteFromQuery, error) {
+	return parseAs[DeleteFromQuery](query, "DELETE")
+}
+
+func parseAs[T any](query, kind string) (T, error) {
+	var zero T
+	stmt, err := Parse(query)
+	if err != nil {
+		return zero, err
+	}
+	if s, ok := stmt.(T); ok {
+		if sb, ok := stmt.(*SelectBuilder); !ok || sb.parent == nil {
+			return s, nil
+		}
+	}
+	return zero, fmt.Errorf("%w: not a %s statement", ErrUnsupportedSyntax, kind)
+}
+
+type sqlParser struct {
+	src  string
+	toks []sqlToken
+	pos  int
+	// placeholders is the number of placeholders read so far.
+	placeholders int
+	// tags are read from a trailing sqlcommenter comment.
+	tags Tags
+}
+
+func newSQLParser(src string) (*sqlParser, error) {
+	p := &sqlParser{src: src}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated comment at offset %d", ErrUnsupportedSyntax, i)
+			}
+			comment := src[i+2 : i+2+end]
+			i += end + 4
+			if rest := strings.TrimSpace(src[i:]); rest == "" || rest == ";" {
+				p.tags = parseTags(comment)
+			}
+		case c == '\'':
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == '\'' {
+					if j+1 < len(src) && src[j+1] == '\'' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("%w: unterminated string at offset %d", ErrUnsupportedSyntax, i)
+			}
+			p.toks = append(p.toks, sqlToken{kind: sqlString, text: src[i : j+1], start: i, end: j + 1})
+			i = j + 1
+		case c == '"':
+			end := strings.IndexByte(src[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated identifier at offset %d", ErrUnsupportedSyntax, i)
+			}
+			p.toks = append(p.toks, sqlToken{kind: sqlIdent, text: src[i : i+end+2], start: i, end: i + end + 2})
+			i += end + 2
+		case c == '?':
+			p.toks = append(p.toks, sqlToken{kind: sqlPlaceholder, text: "?", start: i, end: i + 1})
+			i++
+		case c == '$' || (c == '@' && i+1 < len(src) && src[i+1] == 'p'):
+			j := i + 1
+			if c == '@' {
+				j++
+			}
+			for j < len(src) && unicode.IsDigit(rune(src[j])) {
+				j++
+			}
+			p.toks = append(p.toks, sqlToken{kind: sqlPlaceholder, text: src[i:j], start: i, end: j})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			p.toks = append(p.toks, sqlToken{kind: sqlIdent, text: src[i:j], start: i, end: j})
+			i = j
+		case unicode.IsDigit(rune(c)) || (c == '-' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			j := i + 1
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			p.toks = append(p.toks, sqlToken{kind: sqlNumber, text: src[i:j], start: i, end: j})
+			i = j
+		default:
+			n := 1
+			for _, op := range []string{"<=", ">=", "<>", "!="} {
+				if strings.HasPrefix(src[i:], op) {
+					n = 2
+				}
+			}
+			p.toks = append(p.toks, sqlToken{kind: sqlPunct, text: src[i : i+n], start: i, end: i + n})
+			i += n
+		}
+	}
+	return p, nil
+}
+
+// parseTags returns the tags of a comment in the sqlcommenter format, e.g.
+// route='%2Fusers',service='api', or nil for another comment.
+func parseTags(comment string) Tags {
+	tags := Tags{}
+	for _, pair := range strings.Split(comment, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+			return nil
+		}
+		key, err := url.PathUnescape(k)
+		if err != nil {
+			return nil
+		}
+		value, err := url.PathUnescape(v[1 : len(v)-1])
+		if err != nil {
+			return nil
+		}
+		tags[key] = value
+	}
+	return tags
+}
+
+func (p *sqlParser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *sqlParser) peek(n int) sqlToken {
+	if p.pos+n >= len(p.toks) {
+		return sqlToken{kind: -1}
+	}
+	return p.toks[p.pos+n]
+}
+
+// keyword reports whether the next tokens are the given keywords.
+func (p *sqlParser) keyword(kws ...string) bool {
+	for i, kw := range kws {
+		t := p.peek(i)
+		if t.kind != sqlIdent || !strings.EqualFold(t.text, kw) {
+			return false
+		}
+	}
+	return true
+}
+
+// accept consumes the given keywords or punctuation when they are next.
+func (p *sqlParser) accept(words ...string) bool {
+	for i, w := range words {
+		t := p.peek(i)
+		if (t.kind != sqlIdent && t.kind != sqlPunct) || !strings.EqualFold(t.text, w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *sqlParser) expect(words ...string) error {
+	if !p.accept(words...) {
+		return p.errorf("expected %s", strings.Join(words, " "))
+	}
+	return nil
+}
+
+func (p *sqlParser) errorf(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if p.eof() {
+		return fmt.Errorf("%w: %s at end of input", ErrUnsupportedSyntax, msg)
+	}
+	t := p.toks[p.pos]
+	return fmt.Errorf("%w: %s at offset %d near %q", ErrUnsupportedSyntax, msg, t.start, t.text)
+}
+
+// name reads a possibly qualified identifier.
+func (p *sqlParser) name() (string, error) {
+	t := p.peek(0)
+	if t.kind != sqlIdent {
+		return "", p.errorf("expected identifier")
+	}
+	p.pos++
+	name := t.text
+	for p.peek(0).kind == sqlPunct && p.peek(0).text == "." {
+		p.pos++
+		t := p.peek(0)
+		if t.kind != sqlIdent && !(t.kind == sqlPunct && t.text == "*") {
+			return "", p.errorf("expected identifier")
+		}
+		p.pos++
+		name += "." + t.text
+	}
+	return name, nil
+}
+
+func (p *sqlParser) nameList() ([]string, error) {
+	var names []string
+	for {
+		n, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+		if !p.accept(",") {
+			return names, nil
+		}
+	}
+}
+
+// expressions reads comma separated expressions up to one of the given
+// keywords and returns their source.
+func (p *sqlParser) expressions(end ...string) ([]string, error) {
+	var exprs []string
+	start, depth := p.pos, 0
+	for {
+		t := p.peek(0)
+		atEnd := p.eof() || (depth == 0 && isWord(t, end...))
+		if atEnd || (depth == 0 && t.kind == sqlPunct && t.text == ",") {
+			if start == p.pos {
+				return nil, p.errorf("expected expression")
+			}
+			exprs = append(exprs, p.src[p.toks[start].start:p.toks[p.pos-1].end])
+			if atEnd {
+				return exprs, nil
+			}
+			p.pos++
+			start = p.pos
+			continue
+		}
+		switch {
+		case t.kind == sqlPlaceholder:
+			return nil, p.errorf("placeholder in a column list")
+		case t.kind == sqlPunct && t.text == "(":
+			depth++
+		case t.kind == sqlPunct && t.text == ")":
+			depth--
+		}
+		p.pos++
+	}
+}
+
+// isWord reports whether t is one of the given keywords or punctuation.
+func isWord(t sqlToken, words ...string) bool {
+	if t.kind != sqlIdent && t.kind != sqlPunct {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(w, t.text) {
+			return true
+		}
+	}
+	return false
+}
+
+// value reads a placeholder or a literal. Literals are returned as bound
+// values, placeholders as nothing.
+func (p *sqlParser) value() ([]any, error) {
+	t := p.peek(0)
+	if t.kind == sqlPlaceholder {
+		p.placeholders++
+		n := strings.TrimPrefix(strings.TrimPrefix(t.text, "$"), "@p")
+		if t.text != "?" && n != strconv.Itoa(p.placeholders) {
+			return nil, p.errorf("placeholder out of order, expected number %d", p.placeholders)
+		}
+		p.pos++
+		return nil, nil
+	}
+
+	if p.placeholders > 0 {
+		return nil, p.errorf("literal after a placeholder")
+	}
+	var v any
+	switch {
+	case t.kind == sqlString:
+		v = strings.ReplaceAll(t.text[1:len(t.text)-1], "''", "'")
+	case t.kind == sqlNumber && strings.Contains(t.text, "."):
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number")
+		}
+		v = f
+	case t.kind == sqlNumber:
+		i, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number")
+		}
+		v = i
+	case p.keyword("TRUE"):
+		v = true
+	case p.keyword("FALSE"):
+		v = false
+	case p.keyword("NULL"):
+		v = nil
+	default:
+		return nil, p.errorf("expected a placeholder or a literal")
+	}
+	p.pos++
+	return []any{v}, nil
+}
+
+var (
+	unaryOperators = []BasicOperator{IsNotNull, IsNull, IsNotTrue, IsTrue, IsNotFalse, IsFalse}
+	wordOperators  = []BasicOperator{IsNotDistinctFrom, NotLike, Like}
+	punctOperators = map[string]BasicOperator{
+		"=":  Equals,
+		"!=": NotEqual,
+		"<>": NotEqual,
+		">":  GreaterThan,
+		">=": GreaterThanOrEqual,
+		"<":  LessThan,
+		"<=": LessThanOrEqual,
+	}
+)
+
+// condition reads a single condition of a WHERE clause, or a group of
+// conditions in parentheses.
+func (p *sqlParser) condition() (*WhereCondition, error) {
+	if p.accept("(") {
+		group, err := p.conditions()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &WhereCondition{group: group}, nil
+	}
+	column, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	c := &WhereCondition{ColumnA: column}
+
+	for _, op := range unaryOperators {
+		if p.accept(strings.Fields(string(op))...) {
+			c.Op = op
+			return c, nil
+		}
+	}
+
+	for _, not := range []bool{false, true} {
+		if not && !p.keyword("NOT", "IN") || !not && !p.keyword("IN") {
+			continue
+		}
+		if not {
+			p.pos++
+		}
+		p.pos++
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		count := 0
+		for !p.accept(")") {
+			if count > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			c.values = append(c.values, v...)
+			count++
+		}
+		c.Op = In(count)
+		if not {
+			c.Op = NotIn(count)
+		}
+		return c, nil
+	}
+
+	var op BasicOperator
+	for _, o := range wordOperators {
+		if p.accept(strings.Fields(string(o))...) {
+			op = o
+			break
+		}
+	}
+	if t := p.peek(0); op == "" && t.kind == sqlPunct {
+		if o, ok := punctOperators[t.text]; ok {
+			op = o
+			p.pos++
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected operator")
+	}
+	c.Op = op
+	if p.peek(0).kind == sqlIdent && !p.keyword("TRUE") && !p.keyword("FALSE") && !p.keyword("NULL") {
+		return nil, p.errorf("comparison of two columns")
+	}
+	if c.values, err = p.value(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// where reads the conditions after WHERE, when there is a WHERE clause.
+func (p *sqlParser) where() (*WhereCondition, error) {
+	if !p.accept("WHERE") {
+		return nil, nil
+	}
+	return p.conditions()
+}
+
+// conditions reads conditions joined by AND and OR.
+func (p *sqlParser) conditions() (*WhereCondition, error) {
+	first, err := p.condition()
+	if err != nil {
+		return nil, err
+	}
+	last := first
+	for {
+		var lo LogicalOperator
+		switch {
+		case p.accept("AND"):
+			lo = And
+		case p.accept("OR"):
+			lo = Or
+		default:
+			return first, nil
+		}
+		next, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		last.nextOp, last.next = lo, next
+		last = next
+	}
+}
+
+// on reads the ON condition of a join: a comparison of two columns, followed
+// by the conditions added by Join.Filter.
+func (p *sqlParser) on() (*WhereCondition, error) {
+	first := &WhereCondition{}
+	var err error
+	if first.ColumnA, err = p.name(); err != nil {
+		return nil, err
+	}
+	op, ok := punctOperators[p.peek(0).text]
+	if !ok || p.peek(0).kind != sqlPunct {
+		return nil, p.errorf("expected operator")
+	}
+	p.pos++
+	first.Op = op
+	if first.ColumnB, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	for last := first; p.accept("AND"); last = last.next {
+		next, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		last.nextOp, last.next = And, next
+	}
+	return first, nil
+}
+
+func (p *sqlParser) returning() ([]string, error) {
+	if !p.accept("RETURNING") {
+		return nil, nil
+	}
+	return p.expressions(";")
+}
+
+var joinTypes = []JoinType{FullOuterJoin, InnerJoin, LeftJoin, RightJoin}
+
+func (p *sqlParser) selectStatement(parent *InsertBuilder) (Statement, error) {
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	s := &SelectBuilder{}
+	if parent != nil {
+		s.parent = parent
+		parent.s = s
+	}
+	if !p.accept("*", "FROM") {
+		columns, err := p.expressions("FROM")
+		if err != nil {
+			return nil, err
+		}
+		s.columns = columns
+		p.pos++
+	}
+
+	var err error
+	if s.table, err = p.name(); err != nil {
+		return nil, err
+	}
+	if p.accept("AS") {
+		if s.alias, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		var j *Join
+		for _, jt := range joinTypes {
+			if p.accept(strings.Fields(string(jt))...) {
+				j = &Join{join: jt, parent: s}
+				break
+			}
+		}
+		if j == nil {
+			break
+		}
+		if j.table, err = p.name(); err != nil {
+			return nil, err
+		}
+		if p.accept("AS") {
+			if j.as, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if p.accept("ON") {
+			if j.on, err = p.on(); err != nil {
+				return nil, err
+			}
+		}
+		s.joins = append(s.joins, j)
+	}
+
+	where, err := p.where()
+	if err != nil {
+		return nil, err
+	}
+	if where != nil {
+		s.WhereBuilder = &WhereBuilder[SelectFromQuery]{parent: s, where: where}
+	}
+
+	if p.accept("ORDER", "BY") {
+		// Columns up to a direction form a term, e.g. a, b DESC.
+		for last := &s.orderBy; ; {
+			term := &Sort{orderBy: Asc}
+			if term.columns, err = p.nameList(); err != nil {
+				return nil, err
+			}
+			switch {
+			case p.accept("ASC"):
+			case p.accept("DESC"):
+				term.orderBy = Desc
+			default:
+				// The direction is always rendered.
+				return nil, p.errorf("expected ASC or DESC")
+			}
+			*last, last = term, &term.next
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	return s, nil
+}
+
+func (p *sqlParser) insertStatement() (Statement, error) {
+	if err := p.expect("INSERT", "INTO"); err != nil {
+		return nil, err
+	}
+	ib := &InsertBuilder{}
+	var err error
+	if ib.table, err = p.name(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if ib.columns, err = p.nameList(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if p.keyword("SELECT") {
+		return p.selectStatement(ib)
+	}
+
+	if err := p.expect("VALUES"); err != nil {
+		return nil, err
+	}
+	bound := false
+	for {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var row []any
+		for i := range ib.columns {
+			if i > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, v...)
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, p.errorf("expected %d values", len(ib.columns))
+		}
+		if len(row) != 0 && len(row) != len(ib.columns) {
+			return nil, p.errorf("row mixing literals and placeholders")
+		}
+		bound = bound || len(row) > 0
+		ib.values = append(ib.values, row)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if !bound && len(ib.values) == 1 {
+		// A single row of placeholders is rendered without values.
+		ib.values = nil
+	}
+
+	if ib.returning, err = p.returning(); err != nil {
+		return nil, err
+	}
+	return ib, nil
+}
+
+func (p *sqlParser) updateStatement() (Statement, error) {
+	if err := p.expect("UPDATE"); err != nil {
+		return nil, err
+	}
+	ub := &UpdateBuilder{}
+	var err error
+	if ub.table, err = p.name(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("SET"); err != nil {
+		return nil, err
+	}
+	for {
+		column, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		ub.columns = append(ub.columns, column)
+		ub.values = append(ub.values, v...)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	where, err := p.where()
+	if err != nil {
+		return nil, err
+	}
+	if where != nil {
+		ub.WhereBuilder = &WhereBuilder[UpdateReturningQuery]{parent: ub, where: where}
+	}
+	if ub.returning, err = p.returning(); err != nil {
+		return nil, err
+	}
+	return ub, nil
+}
+
+func (p *sqlParser) deleteStatement() (Statement, error) {
+	if err := p.expect("DELETE", "FROM"); err != nil {
+		return nil, err
+	}
+	d := &DeleteBuilder{}
+	var err error
+	if d.table, err = p.name(); err != nil {
+		return nil, err
+	}
+	where, err := p.where()
+	if err != nil {
+		return nil, err
+	}
+	if where != nil {
+		d.WhereBuilder = &WhereBuilder[DeleteFromQuery]{parent: d, where: where}
+	}
+	return d, nil
+}
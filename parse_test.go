@@ -0,0 +1,128 @@
+package sqlbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("case=round trip", func(t *testing.T) {
+		for _, q := range []Statement{
+			Select().From("users"),
+			Select("id", "count(*) AS n", "coalesce(name, '')").From("users").As("u"),
+			Select("u.id", "r.name").From("users").As("u").
+				InnerJoin("roles").As("r").On("r.id", "u.role_id").
+				LeftJoin("teams").On("teams.id", "u.team_id").
+				FullOuterJoin("audit").On("audit.user_id", "u.id").
+				Where("u.id", In(3)).And("u.deleted_at", IsNull).Or("r.name", Like).
+				OrderBy(Desc, "u.id", "r.name"),
+			Select("id").From("users").Where("active", IsNotTrue).And("score", GreaterThanOrEqual).And("id", NotIn(2)),
+			Insert("id", "name").Into("users").Returning("id"),
+			Insert("id", "name").Into("users").Values().Values(),
+			Insert("id", "name").Into("users").Select("id", "name").From("accounts").Where("id", LessThan),
+			Update("users").Set("name", "email").Where("id", Equals).Parent().Returning("id", "name"),
+			Update("users").Set("name"),
+			Delete().From("users").Where("id", NotEqual).Or("name", IsNotDistinctFrom),
+			Delete().From("users"),
+			Select("id").From("users").Where("id", Equals).Tag("route", "/users/{id}").Tag("service", "api, v2"),
+			Select("id").From("users").Where("org_id", Equals).Parent().
+				Filter(AnyOf(Cond("x", Equals), AllOf(Cond("y", Equals), Cond("z", IsNull)))).
+				OrderBy(Asc, "name").ThenBy(Desc, "created_at", "id"),
+		} {
+			parsed, err := Parse(q.SQL())
+			require.NoError(t, err, q.SQL())
+			require.Equal(t, q.SQL(), parsed.SQL())
+		}
+
+		q := Select("u.id").From("users").As("u").LeftJoin("roles").As("r").On("r.id", "u.role_id").(*SelectBuilder)
+		q.GetJoins()[0].Filter("r.org_id", Equals, 7)
+		q.GetJoins()[0].Filter("r.deleted_at", IsNull)
+		q.Where("u.id", Equals)
+		parsed, err := Parse(q.SQL())
+		require.NoError(t, err)
+		require.Equal(t, "SELECT u.id FROM users AS u LEFT JOIN roles AS r ON r.id = u.role_id AND r.org_id = $1 AND r.deleted_at IS NULL WHERE u.id = $2", parsed.SQL())
+	})
+
+	t.Run("case=modify parsed query", func(t *testing.T) {
+		q, err := ParseSelect("SELECT id, name FROM users WHERE id = $1 ORDER BY id ASC")
+		require.NoError(t, err)
+		c := q.Clone().InnerJoin("roles").On("roles.id", "users.role_id").OrderBy(Desc, "name")
+		require.Equal(t, "SELECT id, name FROM users INNER JOIN roles ON roles.id = users.role_id WHERE id = $1 ORDER BY name DESC", c.SQL())
+		require.Equal(t, "SELECT id, name FROM users WHERE id = $1 ORDER BY id ASC", q.SQL())
+
+		u, err := ParseUpdate("UPDATE users SET name = $1 WHERE id = $2")
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET name = $1 WHERE id = $2 RETURNING id", u.Clone().Returning("id").SQL())
+		require.Equal(t, "UPDATE users SET name = $1 WHERE id = $2", u.SQL())
+	})
+
+	t.Run("case=literals become bound values", func(t *testing.T) {
+		q, err := Parse("select id from users where name = 'o''neil' and score > -1.5 and active = true and id in (1, 2) and role = $1;")
+		require.NoError(t, err)
+		require.Equal(t, "SELECT id FROM users WHERE name = $1 AND score > $2 AND active = $3 AND id IN ($4, $5) AND role = $6", q.SQL())
+		require.Equal(t, []any{"o'neil", -1.5, true, int64(1), int64(2)}, q.Args())
+
+		i, err := ParseInsert("INSERT INTO users (id, name) VALUES (1, 'a'), (2, NULL)")
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4)", i.SQL())
+		require.Equal(t, []any{int64(1), "a", int64(2), nil}, i.Args())
+	})
+
+	t.Run("case=groups and sort terms", func(t *testing.T) {
+		q, err := ParseSelect("SELECT id FROM users WHERE (x = $1 OR y = $2) AND ((z = $3)) ORDER BY a ASC, b DESC")
+		require.NoError(t, err)
+		require.Equal(t, "SELECT id FROM users WHERE (x = $1 OR y = $2) AND ((z = $3)) ORDER BY a ASC, b DESC", q.SQL())
+		require.Equal(t, "SELECT id FROM users WHERE (x = $1 OR y = $2) AND ((z = $3)) ORDER BY a ASC, b DESC, c ASC",
+			q.ThenBy(Asc, "c").SQL())
+	})
+
+	t.Run("case=comments", func(t *testing.T) {
+		q, err := Parse("SELECT id /* primary key */ FROM users -- all of them\nWHERE id = $1 /*route='%2Fusers'*/;")
+		require.NoError(t, err)
+		require.Equal(t, "SELECT id FROM users WHERE id = $1 /*route='%2Fusers'*/", q.SQL())
+		require.Equal(t, Tags{"route": "/users"}, q.(*SelectBuilder).GetTags())
+
+		q, err = Parse("SELECT id FROM users /* not tags */")
+		require.NoError(t, err)
+		require.Equal(t, "SELECT id FROM users", q.SQL())
+	})
+
+	t.Run("case=other dialects", func(t *testing.T) {
+		q, err := Parse("DELETE FROM users WHERE id = ? AND name = ?")
+		require.NoError(t, err)
+		require.Equal(t, "DELETE FROM users WHERE id = $1 AND name = $2", q.SQL())
+
+		q, err = Parse("UPDATE users SET name = @p1 WHERE id = @p2")
+		require.NoError(t, err)
+		s, err := q.Build(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET name = @p1 WHERE id = @p2", s)
+	})
+
+	t.Run("case=unsupported", func(t *testing.T) {
+		for _, query := range []string{
+			"",
+			"CREATE TABLE users (id INT)",
+			"SELECT id FROM users /* unterminated",
+			"SELECT id FROM users WHERE (id = $1 OR id = $2",
+			"SELECT id FROM users ORDER BY id ASC, name",
+			"SELECT id FROM users WHERE id = $2 AND name = $1",
+			"SELECT id FROM users WHERE id = $1 AND name = 'a'",
+			"SELECT id FROM users WHERE id = other_id",
+			"SELECT id FROM users ORDER BY id",
+			"SELECT id FROM users LIMIT 1",
+			"INSERT INTO users (id, name) VALUES ('a', $1)",
+			"INSERT INTO users (id, name) VALUES ($1)",
+			"SELECT 'unterminated FROM users",
+		} {
+			_, err := Parse(query)
+			require.ErrorIs(t, err, ErrUnsupportedSyntax, query)
+		}
+
+		_, err := ParseSelect("DELETE FROM users")
+		require.ErrorIs(t, err, ErrUnsupportedSyntax)
+		_, err = ParseInsert("INSERT INTO users (id) SELECT id FROM accounts")
+		require.ErrorIs(t, err, ErrUnsupportedSyntax)
+	})
+}
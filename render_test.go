@@ -0,0 +1,74 @@
+package sqlbuilder
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// These tests are meant to be run with `go test -race`.
+func TestRenderIdempotent(t *testing.T) {
+	cases := map[string]interface{ SQL() string }{
+		"select": Select("id", "username").From("users").As("u").
+			InnerJoin("roles").As("r").On("r.id", "u.role_id").
+			Where("u.id", Equals).And("u.name", In(3)).OrderBy(Asc, "u.id"),
+		"insert":          Insert("user_id", "name").Into("users").Values(1, "foo").Returning("name"),
+		"insert select":   Insert("user_id", "name").Into("users").Select("id", "name").From("users").Where("id", Equals),
+		"update":          Update("users").Set("id", "name").Where("id", Equals).Or("email", NotIn(2)),
+		"update no where": Update("users").Set("name"),
+		"delete":          Delete().From("users").Where("id", NotEqual).And("name", In(3)),
+		"delete no where": Delete().From("users"),
+	}
+
+	for name, q := range cases {
+		t.Run("case="+name+" rendered twice", func(t *testing.T) {
+			first := q.SQL()
+			require.Equal(t, first, q.SQL())
+		})
+
+		t.Run("case="+name+" rendered concurrently", func(t *testing.T) {
+			expected := q.SQL()
+
+			var wg sync.WaitGroup
+			results := make([]string, 64)
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i] = q.SQL()
+				}(i)
+			}
+			wg.Wait()
+
+			for _, r := range results {
+				require.Equal(t, expected, r)
+			}
+		})
+	}
+
+	t.Run("case=update placeholders restart on every render", func(t *testing.T) {
+		q := Update("users").Set("id").Where("id", Equals)
+		require.Equal(t, "UPDATE users SET id = $1 WHERE id = $2", q.SQL())
+		require.Equal(t, "UPDATE users SET id = $1 WHERE id = $2", q.SQL())
+	})
+}
+
+func TestRenderUnaryOperators(t *testing.T) {
+	q := Select("id").From("users").Where("deleted_at", IsNull).And("active", IsTrue).And("id", Equals)
+	require.Equal(t, "SELECT id FROM users WHERE deleted_at IS NULL AND active IS TRUE AND id = $1", q.SQL())
+}
+
+func TestWhereSQL(t *testing.T) {
+	q := Select("id").From("users").(*SelectBuilder)
+	q.Where("id", Equals).And("name", In(2))
+
+	var sb strings.Builder
+	WhereSQL(q, &sb)
+	require.Equal(t, " WHERE id = $1 AND name IN ($2, $3)", sb.String())
+
+	sb.Reset()
+	WhereSQL(q, &sb)
+	require.Equal(t, " WHERE id = $1 AND name IN ($2, $3)", sb.String())
+}
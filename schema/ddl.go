@@ -0,0 +1,701 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type (
+	tokenKind int
+	token     struct {
+		kind  tokenKind
+		text  string
+		start int
+		end   int
+	}
+)
+
+const (
+	tokIdent tokenKind = iota
+	tokQuoted
+	tokString
+	tokNumber
+	tokPunct
+)
+
+// ParseDDL reads the CREATE TABLE and CREATE INDEX statements in src, and
+// the constraints and columns added by ALTER TABLE ... ADD as written by
+// pg_dump. Other statements are skipped. Tables of the default Postgres
+// schema public are named without it, e.g. public.users is users.
+func ParseDDL(src string) (*Schema, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ddlParser{src: src, toks: toks}
+	s := &Schema{}
+	type tableIndex struct {
+		table string
+		index *Index
+	}
+	var indexes []tableIndex
+	var alters []*Table
+	for !p.eof() {
+		switch {
+		case p.accept("ALTER", "TABLE"):
+			t, err := p.alterTable()
+			if err != nil {
+				return nil, err
+			}
+			alters = append(alters, t)
+		case p.accept("CREATE", "TABLE"):
+			t, err := p.createTable()
+			if err != nil {
+				return nil, err
+			}
+			s.Tables = append(s.Tables, t)
+		case p.accept("CREATE", "INDEX"), p.accept("CREATE", "UNIQUE", "INDEX"):
+			unique := strings.EqualFold(p.toks[p.pos-2].text, "UNIQUE")
+			table, i, err := p.createIndex()
+			if err != nil {
+				return nil, err
+			}
+			i.Unique = unique
+			indexes = append(indexes, tableIndex{table, i})
+		}
+		p.skipStatement()
+	}
+
+	for _, a := range alters {
+		t := s.Table(a.Name)
+		if t == nil {
+			return nil, fmt.Errorf("schema: ALTER TABLE on unknown table %s", a.Name)
+		}
+		t.Columns = append(t.Columns, a.Columns...)
+		if a.PrimaryKey != nil {
+			t.PrimaryKey = a.PrimaryKey
+		}
+		t.Unique = append(t.Unique, a.Unique...)
+		t.ForeignKeys = append(t.ForeignKeys, a.ForeignKeys...)
+		t.Checks = append(t.Checks, a.Checks...)
+	}
+
+	// Indexes may be created before their table in the source.
+	for _, i := range indexes {
+		t := s.Table(i.table)
+		if t == nil {
+			return nil, fmt.Errorf("schema: index %s on unknown table %s", i.index.Name, i.table)
+		}
+		t.Indexes = append(t.Indexes, i.index)
+	}
+	return s, nil
+}
+
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("schema: unterminated comment at offset %d", i)
+			}
+			i += end + 4
+		case c == '\'':
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == '\'' {
+					if j+1 < len(src) && src[j+1] == '\'' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("schema: unterminated string at offset %d", i)
+			}
+			toks = append(toks, token{kind: tokString, text: src[i : j+1], start: i, end: j + 1})
+			i = j + 1
+		case c == '"' || c == '`' || c == '[':
+			closing := map[byte]byte{'"': '"', '`': '`', '[': ']'}[c]
+			if c == '[' && i+1 < len(src) && (src[i+1] == ']' || unicode.IsDigit(rune(src[i+1]))) {
+				// Array type suffix, e.g. TEXT[].
+				toks = append(toks, token{kind: tokPunct, text: "[", start: i, end: i + 1})
+				i++
+				continue
+			}
+			end := strings.IndexByte(src[i+1:], closing)
+			if end < 0 {
+				return nil, fmt.Errorf("schema: unterminated identifier at offset %d", i)
+			}
+			toks = append(toks, token{kind: tokQuoted, text: src[i+1 : i+1+end], start: i, end: i + end + 2})
+			i += end + 2
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '$' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j], start: i, end: j})
+			i = j
+		case unicode.IsDigit(rune(c)):
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[i:j], start: i, end: j})
+			i = j
+		case c == '$' && dollarTag(src[i:]) != "":
+			// Dollar quoted string, e.g. the body of a function in a dump.
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("schema: unterminated string at offset %d", i)
+			}
+			j := i + len(tag) + end + len(tag)
+			toks = append(toks, token{kind: tokString, text: src[i:j], start: i, end: j})
+			i = j
+		case c == ':' && strings.HasPrefix(src[i:], "::"):
+			toks = append(toks, token{kind: tokPunct, text: "::", start: i, end: i + 2})
+			i += 2
+		default:
+			toks = append(toks, token{kind: tokPunct, text: string(c), start: i, end: i + 1})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+// dollarTag returns the tag opening the dollar quoted string at the start of
+// s, e.g. $$ or $body$, or "".
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		switch c := rune(s[j]); {
+		case c == '$':
+			return s[:j+1]
+		case c != '_' && !unicode.IsLetter(c) && (j == 1 || !unicode.IsDigit(c)):
+			return ""
+		}
+	}
+	return ""
+}
+
+type ddlParser struct {
+	src  string
+	toks []token
+	pos  int
+}
+
+func (p *ddlParser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *ddlParser) peek(n int) (token, bool) {
+	if p.pos+n >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos+n], true
+}
+
+func (p *ddlParser) peekKeyword(n int, kw string) bool {
+	t, ok := p.peek(n)
+	return ok && t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func (p *ddlParser) keyword(kw string) bool {
+	return p.peekKeyword(0, kw)
+}
+
+func (p *ddlParser) punct(s string) bool {
+	t, ok := p.peek(0)
+	return ok && t.kind == tokPunct && t.text == s
+}
+
+// accept consumes the given keywords when they are next.
+func (p *ddlParser) accept(kws ...string) bool {
+	for i, kw := range kws {
+		if !p.peekKeyword(i, kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *ddlParser) expect(s string) error {
+	if !p.punct(s) {
+		return p.errorf("expected %q", s)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *ddlParser) errorf(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if t, ok := p.peek(0); ok {
+		return fmt.Errorf("schema: %s at offset %d near %q", msg, t.start, t.text)
+	}
+	return fmt.Errorf("schema: %s at end of input", msg)
+}
+
+func (p *ddlParser) skipStatement() {
+	depth := 0
+	for !p.eof() {
+		t := p.toks[p.pos]
+		p.pos++
+		switch {
+		case t.kind != tokPunct:
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case t.text == ";" && depth <= 0:
+			return
+		}
+	}
+}
+
+// name reads a possibly schema qualified identifier.
+func (p *ddlParser) name() (string, error) {
+	t, ok := p.peek(0)
+	if !ok || (t.kind != tokIdent && t.kind != tokQuoted) {
+		return "", p.errorf("expected identifier")
+	}
+	p.pos++
+	name := t.text
+	if t.kind == tokIdent {
+		name = strings.ToLower(name)
+	}
+	if p.punct(".") {
+		p.pos++
+		rest, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		return name + "." + rest, nil
+	}
+	return name, nil
+}
+
+// tableName reads the name of a table, without the default schema public.
+func (p *ddlParser) tableName() (string, error) {
+	name, err := p.name()
+	return strings.TrimPrefix(name, "public."), err
+}
+
+func (p *ddlParser) nameList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		n, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		// Skip index options such as ASC or a length prefix.
+		for !p.punct(",") && !p.punct(")") && !p.eof() {
+			p.skipTerm()
+		}
+		names = append(names, n)
+		if p.punct(")") {
+			p.pos++
+			return names, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipTerm skips a single token or a parenthesised group.
+func (p *ddlParser) skipTerm() {
+	if !p.punct("(") {
+		p.pos++
+		return
+	}
+	depth := 0
+	for !p.eof() {
+		t := p.toks[p.pos]
+		p.pos++
+		if t.kind == tokPunct && t.text == "(" {
+			depth++
+		} else if t.kind == tokPunct && t.text == ")" {
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// text returns the source of the tokens from start up to the current one.
+func (p *ddlParser) text(start int) string {
+	if start >= p.pos {
+		return ""
+	}
+	return p.src[p.toks[start].start:p.toks[p.pos-1].end]
+}
+
+func (p *ddlParser) createTable() (*Table, error) {
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{Name: name}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := p.tableElement(t); err != nil {
+			return nil, err
+		}
+		if p.punct(")") {
+			p.pos++
+			return t, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// alterTable reads an ALTER TABLE statement after its TABLE keyword and
+// returns a table holding the columns and constraints it adds. Reading stops
+// at the first other action, the rest of the statement is skipped.
+func (p *ddlParser) alterTable() (*Table, error) {
+	p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	name, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{Name: name}
+	for p.accept("ADD") {
+		next, _ := p.peek(1)
+		switch {
+		case p.accept("COLUMN"):
+			p.accept("IF", "NOT", "EXISTS")
+			err = p.column(t)
+		case p.keyword("CONSTRAINT"), p.keyword("PRIMARY"), p.keyword("FOREIGN"), p.keyword("CHECK"),
+			p.keyword("UNIQUE") && next.kind == tokPunct && next.text == "(":
+			err = p.tableElement(t)
+		default:
+			return t, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !p.punct(",") {
+			break
+		}
+		p.pos++
+	}
+	return t, nil
+}
+
+var columnConstraints = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "UNIQUE": true,
+	"REFERENCES": true, "CHECK": true, "CONSTRAINT": true, "GENERATED": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "COLLATE": true, "IDENTITY": true,
+}
+
+func (p *ddlParser) atColumnEnd() bool {
+	if p.eof() || p.punct(",") || p.punct(")") {
+		return true
+	}
+	t := p.toks[p.pos]
+	return t.kind == tokIdent && columnConstraints[strings.ToUpper(t.text)]
+}
+
+func (p *ddlParser) tableElement(t *Table) error {
+	var constraint string
+	if p.accept("CONSTRAINT") {
+		var err error
+		if constraint, err = p.name(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		cols, err := p.nameList()
+		if err != nil {
+			return err
+		}
+		t.PrimaryKey = cols
+		return nil
+	case p.accept("UNIQUE"):
+		p.accept("KEY")
+		cols, err := p.nameList()
+		if err != nil {
+			return err
+		}
+		t.Unique = append(t.Unique, cols)
+		return nil
+	case p.accept("FOREIGN", "KEY"):
+		cols, err := p.nameList()
+		if err != nil {
+			return err
+		}
+		fk, err := p.references(cols)
+		if err != nil {
+			return err
+		}
+		fk.Name = constraint
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+		return nil
+	case p.accept("CHECK"):
+		t.Checks = append(t.Checks, p.group())
+		return nil
+	}
+
+	return p.column(t)
+}
+
+// group returns the source inside the parenthesised group at the current
+// token.
+func (p *ddlParser) group() string {
+	start := p.pos
+	p.skipTerm()
+	text := p.text(start)
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "("), ")"))
+}
+
+func (p *ddlParser) references(cols []string) (ForeignKey, error) {
+	fk := ForeignKey{Columns: cols}
+	if !p.accept("REFERENCES") {
+		return fk, p.errorf("expected REFERENCES")
+	}
+	var err error
+	if fk.RefTable, err = p.tableName(); err != nil {
+		return fk, err
+	}
+	if p.punct("(") {
+		if fk.RefColumns, err = p.nameList(); err != nil {
+			return fk, err
+		}
+	}
+	for {
+		switch {
+		case p.accept("ON", "DELETE"):
+			fk.OnDelete = p.referentialAction()
+		case p.accept("ON", "UPDATE"):
+			fk.OnUpdate = p.referentialAction()
+		default:
+			return fk, nil
+		}
+	}
+}
+
+func (p *ddlParser) referentialAction() string {
+	for _, action := range [][]string{{"SET", "NULL"}, {"SET", "DEFAULT"}, {"NO", "ACTION"}, {"CASCADE"}, {"RESTRICT"}} {
+		if p.accept(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	return ""
+}
+
+func (p *ddlParser) column(t *Table) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	c := &Column{Name: name}
+	t.Columns = append(t.Columns, c)
+
+	start := p.pos
+	for !p.atColumnEnd() {
+		p.skipTerm()
+	}
+	c.Type = p.text(start)
+	switch strings.ToUpper(c.Type) {
+	case "SERIAL", "BIGSERIAL", "SMALLSERIAL":
+		c.Identity = true
+		c.NotNull = true
+	}
+
+	var constraint string
+	for !p.eof() && !p.punct(",") && !p.punct(")") {
+		switch {
+		case p.accept("CONSTRAINT"):
+			if constraint, err = p.name(); err != nil {
+				return err
+			}
+			continue
+		case p.accept("NOT", "NULL"):
+			c.NotNull = true
+		case p.accept("NULL"):
+		case p.accept("DEFAULT"):
+			start := p.pos
+			p.skipTerm()
+			for !p.atColumnEnd() {
+				p.skipTerm()
+			}
+			c.Default = p.text(start)
+		case p.accept("PRIMARY", "KEY"):
+			t.PrimaryKey = []string{c.Name}
+			c.NotNull = true
+			p.accept("AUTOINCREMENT")
+		case p.accept("UNIQUE"):
+			t.Unique = append(t.Unique, []string{c.Name})
+		case p.keyword("REFERENCES"):
+			fk, err := p.references([]string{c.Name})
+			if err != nil {
+				return err
+			}
+			fk.Name = constraint
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		case p.accept("CHECK"):
+			t.Checks = append(t.Checks, p.group())
+		case p.accept("GENERATED"):
+			p.accept("ALWAYS")
+			p.accept("BY", "DEFAULT")
+			if !p.accept("AS") {
+				return p.errorf("expected AS")
+			}
+			if p.accept("IDENTITY") {
+				c.Identity = true
+				if p.punct("(") {
+					p.skipTerm()
+				}
+				break
+			}
+			// Generated column.
+			p.skipTerm()
+			if !p.accept("STORED") {
+				p.accept("VIRTUAL")
+			}
+		case p.accept("AUTO_INCREMENT"), p.accept("AUTOINCREMENT"), p.accept("IDENTITY"):
+			c.Identity = true
+			if p.punct("(") {
+				p.skipTerm()
+			}
+		case p.accept("COLLATE"):
+			p.skipTerm()
+		default:
+			return p.errorf("unexpected token in column %s", c.Name)
+		}
+		constraint = ""
+	}
+	return nil
+}
+
+// createIndex reads a CREATE INDEX statement after its INDEX keyword and
+// returns the index with the name of its table.
+func (p *ddlParser) createIndex() (string, *Index, error) {
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return "", nil, err
+	}
+	i := &Index{Name: name}
+	if p.accept("USING") {
+		// MySQL puts the method before the table.
+		if p.eof() {
+			return "", nil, p.errorf("expected index method")
+		}
+		i.Method = strings.ToLower(p.toks[p.pos].text)
+		p.pos++
+	}
+	if !p.accept("ON") {
+		return "", nil, p.errorf("expected ON")
+	}
+	p.accept("ONLY")
+	table, err := p.tableName()
+	if err != nil {
+		return "", nil, err
+	}
+	if p.accept("USING") {
+		if p.eof() {
+			return "", nil, p.errorf("expected index method")
+		}
+		i.Method = strings.ToLower(p.toks[p.pos].text)
+		p.pos++
+	}
+
+	if err := p.expect("("); err != nil {
+		return "", nil, err
+	}
+	for {
+		col, err := p.indexColumn()
+		if err != nil {
+			return "", nil, err
+		}
+		i.Columns = append(i.Columns, col)
+		if p.punct(")") {
+			p.pos++
+			break
+		}
+		if err := p.expect(","); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if p.accept("INCLUDE") {
+		if i.Include, err = p.nameList(); err != nil {
+			return "", nil, err
+		}
+	}
+	if p.accept("WHERE") {
+		start := p.pos
+		for !p.eof() && !p.punct(";") {
+			p.skipTerm()
+		}
+		i.Where = p.text(start)
+	}
+	return table, i, nil
+}
+
+func (p *ddlParser) indexColumn() (IndexColumn, error) {
+	var col IndexColumn
+	next, _ := p.peek(1)
+	switch {
+	case p.punct("("):
+		col.Expr = p.group()
+	case next.kind != tokPunct || next.text != "(":
+		name, err := p.name()
+		if err != nil {
+			return col, err
+		}
+		col.Expr = name
+	default:
+		// Function call, e.g. lower(email).
+		start := p.pos
+		p.pos++
+		p.skipTerm()
+		col.Expr = p.text(start)
+	}
+
+	for !p.eof() && !p.punct(",") && !p.punct(")") {
+		switch {
+		case p.accept("ASC"):
+		case p.accept("DESC"):
+			col.Desc = true
+		case p.accept("NULLS", "FIRST"):
+			col.Nulls = "FIRST"
+		case p.accept("NULLS", "LAST"):
+			col.Nulls = "LAST"
+		case p.accept("COLLATE"):
+			p.skipTerm()
+		default:
+			start := p.pos
+			p.skipTerm()
+			col.OpClass = p.text(start)
+		}
+	}
+	return col, nil
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDDL(t *testing.T) {
+	t.Run("case=columns and constraints", func(t *testing.T) {
+		s, err := ParseDDL(`
+-- users of the application
+CREATE TABLE IF NOT EXISTS users (
+	id BIGSERIAL PRIMARY KEY,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	name TEXT,
+	score NUMERIC(10, 2) DEFAULT 0.5 NOT NULL,
+	tags TEXT[],
+	role_id INTEGER REFERENCES roles (id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	CONSTRAINT score_positive CHECK (score >= 0)
+);
+
+CREATE INDEX users_email ON users (email);
+
+CREATE TABLE "Roles" (
+	id INT GENERATED BY DEFAULT AS IDENTITY,
+	name TEXT COLLATE "C" NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (name)
+);`)
+		require.NoError(t, err)
+		require.Len(t, s.Tables, 2)
+
+		users := s.Table("users")
+		require.NotNil(t, users)
+		require.Equal(t, []string{"id"}, users.PrimaryKey)
+		require.Equal(t, [][]string{{"email"}}, users.Unique)
+		require.Equal(t, []string{"score >= 0"}, users.Checks)
+		require.Equal(t, []ForeignKey{{Columns: []string{"role_id"}, RefTable: "roles", RefColumns: []string{"id"}, OnDelete: "CASCADE"}}, users.ForeignKeys)
+
+		require.Equal(t, &Column{Name: "id", Type: "BIGSERIAL", NotNull: true, Identity: true}, users.Column("id"))
+		require.Equal(t, &Column{Name: "email", Type: "VARCHAR(255)", NotNull: true}, users.Column("email"))
+		require.Equal(t, &Column{Name: "name", Type: "TEXT"}, users.Column("name"))
+		require.Equal(t, &Column{Name: "score", Type: "NUMERIC(10, 2)", NotNull: true, Default: "0.5"}, users.Column("score"))
+		require.Equal(t, &Column{Name: "tags", Type: "TEXT[]"}, users.Column("tags"))
+		require.Equal(t, &Column{Name: "created_at", Type: "TIMESTAMP WITH TIME ZONE", NotNull: true, Default: "now()"}, users.Column("created_at"))
+
+		roles := s.Table("Roles")
+		require.NotNil(t, roles)
+		require.Equal(t, []string{"id"}, roles.PrimaryKey)
+		require.True(t, roles.Column("id").Identity)
+		require.True(t, roles.Column("name").NotNull)
+	})
+
+	t.Run("case=indexes", func(t *testing.T) {
+		s, err := ParseDDL(`
+CREATE UNIQUE INDEX CONCURRENTLY users_email ON ONLY users USING btree ((lower(email)), created_at DESC NULLS LAST)
+	INCLUDE (name) WHERE deleted_at IS NULL;
+CREATE INDEX users_name ON users (name text_pattern_ops, "Role");
+CREATE INDEX users_bio USING HASH ON users (md5(bio));
+CREATE TABLE users (
+	id INT PRIMARY KEY,
+	role_id INT CONSTRAINT users_role_fk REFERENCES roles (id),
+	CONSTRAINT users_team_fk FOREIGN KEY (team_id) REFERENCES teams (id)
+);`)
+		require.NoError(t, err)
+
+		users := s.Table("users")
+		require.Equal(t, []*Index{
+			{
+				Name:   "users_email",
+				Unique: true,
+				Method: "btree",
+				Columns: []IndexColumn{
+					{Expr: "lower(email)"},
+					{Expr: "created_at", Desc: true, Nulls: "LAST"},
+				},
+				Include: []string{"name"},
+				Where:   "deleted_at IS NULL",
+			},
+			{Name: "users_name", Columns: []IndexColumn{{Expr: "name", OpClass: "text_pattern_ops"}, {Expr: "Role"}}},
+			{Name: "users_bio", Method: "hash", Columns: []IndexColumn{{Expr: "md5(bio)"}}},
+		}, users.Indexes)
+		require.Equal(t, "users_role_fk", users.ForeignKeys[0].Name)
+		require.Equal(t, "users_team_fk", users.ForeignKeys[1].Name)
+
+		_, err = ParseDDL(`CREATE INDEX i ON users (id);`)
+		require.Error(t, err)
+	})
+
+	t.Run("case=syntax error", func(t *testing.T) {
+		_, err := ParseDDL(`CREATE TABLE users (id INT NOT NULL`)
+		require.Error(t, err)
+	})
+}
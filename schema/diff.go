@@ -0,0 +1,553 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Benehiko/sqlbuilder"
+	"github.com/Benehiko/sqlbuilder/migrate"
+)
+
+type (
+	// Change is a single statement of a Diff.
+	Change struct {
+		Statement sqlbuilder.Statement
+		// Description summarizes the change, e.g. drop column users.legacy.
+		Description string
+		// Destructive is set for changes which may lose data, such as
+		// dropping a table or a column or changing the type of a column.
+		Destructive bool
+	}
+
+	// Diff holds the changes needed to go from one schema to another, in
+	// the order they must be applied.
+	Diff struct {
+		Changes []Change
+		from    *Schema
+		to      *Schema
+		hints   hints
+	}
+
+	DiffOption func(h *hints)
+
+	hints struct {
+		tables  []rename
+		columns []rename
+	}
+	rename struct {
+		table, from, to string
+	}
+)
+
+var (
+	ErrInvalidHint       = errors.New("schema: rename hint doesn't match the schemas")
+	ErrUnnamedConstraint = errors.New("schema: can't drop a constraint without name")
+)
+
+// RenameTable tells Compare that table from was renamed to, instead of being
+// dropped and created again.
+func RenameTable(from, to string) DiffOption {
+	return func(h *hints) {
+		h.tables = append(h.tables, rename{from: from, to: to})
+	}
+}
+
+// RenameColumn tells Compare that column from of table, as named in the
+// target schema, was renamed to.
+func RenameColumn(table, from, to string) DiffOption {
+	return func(h *hints) {
+		h.columns = append(h.columns, rename{table: table, from: from, to: to})
+	}
+}
+
+// oldTable returns the name table of the target schema had before.
+func (h hints) oldTable(table string) string {
+	for _, r := range h.tables {
+		if r.to == table {
+			return r.from
+		}
+	}
+	return table
+}
+
+// oldColumn returns the name column of table of the target schema had
+// before.
+func (h hints) oldColumn(table, column string) string {
+	for _, r := range h.columns {
+		if r.table == table && r.to == column {
+			return r.from
+		}
+	}
+	return column
+}
+
+func (h hints) reverse() hints {
+	var r hints
+	for _, t := range h.tables {
+		r.tables = append(r.tables, rename{from: t.to, to: t.from})
+	}
+	for _, c := range h.columns {
+		r.columns = append(r.columns, rename{table: h.oldTable(c.table), from: c.to, to: c.from})
+	}
+	return r
+}
+
+// FromStatements describes the tables and indexes created by the given
+// CREATE TABLE and CREATE INDEX statements rendered for d.
+func FromStatements(d *sqlbuilder.Dialect, stmts ...sqlbuilder.Statement) (*Schema, error) {
+	var src strings.Builder
+	for _, stmt := range stmts {
+		query, err := stmt.Build(sqlbuilder.WithDialect(d))
+		if err != nil {
+			return nil, err
+		}
+		src.WriteString(query)
+		src.WriteString(";\n")
+	}
+	return ParseDDL(src.String())
+}
+
+// Compare returns the changes turning the schema from into the schema to.
+// Tables and columns missing from to are dropped unless a RenameTable or
+// RenameColumn hint says they were renamed.
+//
+// Primary keys of existing tables aren't compared, and unique and check
+// constraints are only created with new tables.
+func Compare(from, to *Schema, opts ...DiffOption) (*Diff, error) {
+	d := &Diff{from: from, to: to}
+	for _, opt := range opts {
+		opt(&d.hints)
+	}
+	for _, r := range d.hints.tables {
+		if from.Table(r.from) == nil || to.Table(r.to) == nil {
+			return nil, fmt.Errorf("%w: table %s to %s", ErrInvalidHint, r.from, r.to)
+		}
+	}
+	for _, r := range d.hints.columns {
+		t, old := to.Table(r.table), from.Table(d.hints.oldTable(r.table))
+		if t == nil || old == nil || t.Column(r.to) == nil || old.Column(r.from) == nil {
+			return nil, fmt.Errorf("%w: column %s.%s to %s", ErrInvalidHint, r.table, r.from, r.to)
+		}
+	}
+
+	type pair struct{ from, to *Table }
+	var pairs []pair
+	var created []*Table
+	matched := map[string]bool{}
+	for _, t := range to.Tables {
+		if old := from.Table(d.hints.oldTable(t.Name)); old != nil {
+			pairs = append(pairs, pair{old, t})
+			matched[old.Name] = true
+		} else {
+			created = append(created, t)
+		}
+	}
+	var dropped []*Table
+	for _, t := range from.Tables {
+		if !matched[t.Name] {
+			dropped = append(dropped, t)
+		}
+	}
+
+	for _, p := range pairs {
+		if p.from.Name != p.to.Name {
+			d.add(sqlbuilder.AlterTable(p.from.Name).RenameTo(p.to.Name), false, "rename table %s to %s", p.from.Name, p.to.Name)
+		}
+	}
+
+	// Indexes and foreign keys go before the columns they may depend on.
+	for _, p := range pairs {
+		for _, i := range p.from.Indexes {
+			if n := p.to.Index(i.Name); n == nil || !i.equal(n) {
+				d.add(sqlbuilder.DropIndex(i.Name).On(p.to.Name), false, "drop index %s", i.Name)
+			}
+		}
+	}
+	for _, p := range pairs {
+		for _, fk := range p.from.ForeignKeys {
+			fk = d.renameColumns(p.to.Name, fk)
+			if slices.ContainsFunc(p.to.ForeignKeys, fk.equal) {
+				continue
+			}
+			if !slices.ContainsFunc(fk.Columns, func(c string) bool { return p.to.Column(c) != nil }) {
+				// Dropped along with its columns.
+				continue
+			}
+			if fk.Name == "" {
+				return nil, fmt.Errorf("%w: foreign key of %s (%s)", ErrUnnamedConstraint, p.to.Name, strings.Join(fk.Columns, ", "))
+			}
+			d.add(sqlbuilder.AlterTable(p.to.Name).DropConstraint(fk.Name), false, "drop foreign key %s", fk.Name)
+		}
+	}
+
+	for _, t := range dependencyOrder(created) {
+		d.add(createTable(t), false, "create table %s", t.Name)
+	}
+
+	for _, p := range pairs {
+		d.compareColumns(p.from, p.to)
+	}
+
+	for _, p := range pairs {
+		for _, fk := range p.to.ForeignKeys {
+			old := slices.IndexFunc(p.from.ForeignKeys, func(o ForeignKey) bool {
+				return d.renameColumns(p.to.Name, o).equal(fk)
+			})
+			if old < 0 {
+				d.add(sqlbuilder.AlterTable(p.to.Name).AddConstraint(foreignKey(fk)), false,
+					"add foreign key %s (%s)", p.to.Name, strings.Join(fk.Columns, ", "))
+			}
+		}
+	}
+
+	for _, p := range pairs {
+		for _, i := range p.to.Indexes {
+			if o := p.from.Index(i.Name); o == nil || !o.equal(i) {
+				d.add(createIndex(p.to.Name, i), false, "create index %s", i.Name)
+			}
+		}
+	}
+	for _, t := range created {
+		for _, i := range t.Indexes {
+			d.add(createIndex(t.Name, i), false, "create index %s", i.Name)
+		}
+	}
+
+	for _, t := range slices.Backward(dependencyOrder(dropped)) {
+		d.add(sqlbuilder.DropTable(t.Name), true, "drop table %s", t.Name)
+	}
+	return d, nil
+}
+
+func (d *Diff) add(stmt sqlbuilder.Statement, destructive bool, format string, args ...any) {
+	d.Changes = append(d.Changes, Change{
+		Statement:   stmt,
+		Description: fmt.Sprintf(format, args...),
+		Destructive: destructive,
+	})
+}
+
+// renameColumns returns fk with the columns of table renamed as hinted.
+func (d *Diff) renameColumns(table string, fk ForeignKey) ForeignKey {
+	fk.Columns = slices.Clone(fk.Columns)
+	for i, c := range fk.Columns {
+		for _, r := range d.hints.columns {
+			if r.table == table && r.from == c {
+				fk.Columns[i] = r.to
+			}
+		}
+	}
+	return fk
+}
+
+func (d *Diff) compareColumns(from, to *Table) {
+	alter := func() *sqlbuilder.AlterTableBuilder {
+		return sqlbuilder.AlterTable(to.Name)
+	}
+
+	kept := map[string]bool{}
+	for _, c := range to.Columns {
+		old := from.Column(d.hints.oldColumn(to.Name, c.Name))
+		if old == nil {
+			d.add(alter().AddColumn(columnDef(c)), false, "add column %s.%s", to.Name, c.Name)
+			continue
+		}
+		kept[old.Name] = true
+
+		if old.Name != c.Name {
+			d.add(alter().RenameColumn(old.Name, c.Name), false, "rename column %s.%s to %s", to.Name, old.Name, c.Name)
+		}
+		if normalizeType(old.Type) != normalizeType(c.Type) {
+			d.add(alter().AlterColumnType(c.Name, sqlbuilder.RawType(c.Type)), true,
+				"change type of column %s.%s from %s to %s", to.Name, c.Name, old.Type, c.Type)
+		}
+		switch {
+		case normalizeExpr(old.Default) == normalizeExpr(c.Default):
+		case c.Default == "":
+			d.add(alter().DropDefault(c.Name), false, "drop default of column %s.%s", to.Name, c.Name)
+		default:
+			d.add(alter().SetDefault(c.Name, c.Default), false, "set default of column %s.%s", to.Name, c.Name)
+		}
+		switch {
+		case old.NotNull == c.NotNull:
+		case c.NotNull:
+			d.add(alter().SetNotNull(c.Name), false, "set column %s.%s not null", to.Name, c.Name)
+		default:
+			d.add(alter().DropNotNull(c.Name), false, "drop not null of column %s.%s", to.Name, c.Name)
+		}
+	}
+
+	for _, c := range from.Columns {
+		if !kept[c.Name] {
+			d.add(alter().DropColumn(c.Name), true, "drop column %s.%s", to.Name, c.Name)
+		}
+	}
+}
+
+// Destructive returns the changes which may lose data.
+func (d *Diff) Destructive() []Change {
+	var changes []Change
+	for _, c := range d.Changes {
+		if c.Destructive {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Reverse returns the diff undoing d.
+func (d *Diff) Reverse() (*Diff, error) {
+	h := d.hints.reverse()
+	return Compare(d.to, d.from, func(r *hints) { *r = h })
+}
+
+// Statements renders the changes for dialect, one statement per element.
+func (d *Diff) Statements(dialect *sqlbuilder.Dialect) ([]string, error) {
+	var stmts []string
+	for _, c := range d.Changes {
+		if a, ok := c.Statement.(*sqlbuilder.AlterTableBuilder); ok {
+			s, err := a.Statements(sqlbuilder.WithDialect(dialect))
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, s...)
+			continue
+		}
+		s, err := c.Statement.Build(sqlbuilder.WithDialect(dialect))
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s)
+	}
+	return stmts, nil
+}
+
+// Migration returns the diff as a migration reverted by the reverse diff.
+func (d *Diff) Migration(version int64, name string) (*migrate.Migration, error) {
+	reverse, err := d.Reverse()
+	if err != nil {
+		return nil, err
+	}
+	m := &migrate.Migration{Version: version, Name: name}
+	for _, c := range d.Changes {
+		m.Up = append(m.Up, c.Statement)
+	}
+	for _, c := range reverse.Changes {
+		m.Down = append(m.Down, c.Statement)
+	}
+	return m, nil
+}
+
+// WriteFiles writes the diff rendered for dialect to the files
+// <version>_<name>.up.sql and <version>_<name>.down.sql in dir, as read by
+// migrate.FromFS. Destructive changes are listed in a comment at the top of
+// each file. The driver must accept several statements at once.
+func (d *Diff) WriteFiles(dir string, version int64, name string, dialect *sqlbuilder.Dialect) error {
+	reverse, err := d.Reverse()
+	if err != nil {
+		return err
+	}
+	base := fmt.Sprintf("%d_%s", version, strings.Join(strings.Fields(name), "_"))
+	for suffix, diff := range map[string]*Diff{".up.sql": d, ".down.sql": reverse} {
+		stmts, err := diff.Statements(dialect)
+		if err != nil {
+			return err
+		}
+
+		var sb strings.Builder
+		for _, c := range diff.Destructive() {
+			sb.WriteString("-- destructive: " + c.Description + "\n")
+		}
+		for _, s := range stmts {
+			sb.WriteString(s + ";\n")
+		}
+		if err := os.WriteFile(filepath.Join(dir, base+suffix), []byte(sb.String()), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fk ForeignKey) equal(o ForeignKey) bool {
+	return slices.Equal(fk.Columns, o.Columns) && fk.RefTable == o.RefTable &&
+		slices.Equal(fk.RefColumns, o.RefColumns) && fk.OnDelete == o.OnDelete && fk.OnUpdate == o.OnUpdate
+}
+
+func (i *Index) equal(o *Index) bool {
+	method := func(m string) string {
+		if m == "" {
+			return "btree"
+		}
+		return strings.ToLower(m)
+	}
+	return i.Unique == o.Unique && method(i.Method) == method(o.Method) &&
+		slices.EqualFunc(i.Columns, o.Columns, func(a, b IndexColumn) bool {
+			a.Expr, b.Expr = normalize(a.Expr), normalize(b.Expr)
+			return a == b
+		}) &&
+		slices.Equal(i.Include, o.Include) && normalize(i.Where) == normalize(o.Where)
+}
+
+// typeAliases maps alternative spellings of types to a single one.
+var typeAliases = map[string]string{
+	"INT":                         "INTEGER",
+	"INT4":                        "INTEGER",
+	"INT8":                        "BIGINT",
+	"INT2":                        "SMALLINT",
+	"BOOL":                        "BOOLEAN",
+	"FLOAT8":                      "DOUBLE PRECISION",
+	"FLOAT4":                      "REAL",
+	"CHARACTER VARYING":           "VARCHAR",
+	"CHARACTER":                   "CHAR",
+	"DECIMAL":                     "NUMERIC",
+	"TIMESTAMP WITH TIME ZONE":    "TIMESTAMPTZ",
+	"TIMESTAMP WITHOUT TIME ZONE": "TIMESTAMP",
+}
+
+func normalizeType(t string) string {
+	t = strings.ToUpper(normalize(t))
+	name, size, _ := strings.Cut(t, "(")
+	name = strings.TrimSpace(name)
+	if alias, ok := typeAliases[name]; ok {
+		name = alias
+	}
+	if size != "" {
+		return name + "(" + strings.ReplaceAll(size, " ", "")
+	}
+	return name
+}
+
+// normalize collapses the whitespace of an expression.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// normalizeExpr is normalize with the expression in upper case outside of
+// its string literals and quoted identifiers, e.g. now() and NOW() are the
+// same.
+func normalizeExpr(s string) string {
+	b := []byte(normalize(s))
+	var quote byte
+	for i, c := range b {
+		switch {
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case c == quote:
+			quote = 0
+		case quote == 0 && 'a' <= c && c <= 'z':
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return string(b)
+}
+
+// dependencyOrder sorts tables so that tables referenced by foreign keys
+// come before the tables referencing them. Cycles keep the given order.
+func dependencyOrder(tables []*Table) []*Table {
+	byName := map[string]*Table{}
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	var ordered []*Table
+	visited := map[string]bool{}
+	var visit func(t *Table)
+	visit = func(t *Table) {
+		if visited[t.Name] {
+			return
+		}
+		visited[t.Name] = true
+		for _, fk := range t.ForeignKeys {
+			if ref, ok := byName[fk.RefTable]; ok {
+				visit(ref)
+			}
+		}
+		ordered = append(ordered, t)
+	}
+	for _, t := range tables {
+		visit(t)
+	}
+	return ordered
+}
+
+func createTable(t *Table) *sqlbuilder.CreateTableBuilder {
+	b := sqlbuilder.CreateTable(t.Name)
+	for _, c := range t.Columns {
+		def := columnDef(c)
+		if len(t.PrimaryKey) == 1 && t.PrimaryKey[0] == c.Name {
+			def.PrimaryKey()
+		}
+		b.Columns(def)
+	}
+	if len(t.PrimaryKey) > 1 {
+		b.Constraints(sqlbuilder.PrimaryKey(t.PrimaryKey...))
+	}
+	for _, u := range t.Unique {
+		b.Constraints(sqlbuilder.Unique(u...))
+	}
+	for _, fk := range t.ForeignKeys {
+		b.Constraints(foreignKey(fk))
+	}
+	for _, c := range t.Checks {
+		b.Constraints(sqlbuilder.Check(c))
+	}
+	return b
+}
+
+func columnDef(c *Column) *sqlbuilder.ColumnDef {
+	def := sqlbuilder.Column(c.Name, sqlbuilder.RawType(c.Type))
+	if c.NotNull {
+		def.NotNull()
+	}
+	if c.Default != "" {
+		def.Default(c.Default)
+	}
+	if c.Identity && !strings.HasSuffix(strings.ToUpper(c.Type), "SERIAL") {
+		def.Identity()
+	}
+	return def
+}
+
+func foreignKey(fk ForeignKey) *sqlbuilder.Constraint {
+	c := sqlbuilder.ForeignKey(fk.Columns...).References(fk.RefTable, fk.RefColumns...)
+	if fk.Name != "" {
+		c.Named(fk.Name)
+	}
+	if fk.OnDelete != "" {
+		c.OnDelete(sqlbuilder.ReferentialAction(fk.OnDelete))
+	}
+	if fk.OnUpdate != "" {
+		c.OnUpdate(sqlbuilder.ReferentialAction(fk.OnUpdate))
+	}
+	return c
+}
+
+func createIndex(table string, i *Index) *sqlbuilder.CreateIndexBuilder {
+	b := sqlbuilder.CreateIndex(i.Name).On(table).Include(i.Include...)
+	if i.Unique {
+		b.Unique()
+	}
+	if i.Method != "" {
+		b.Using(sqlbuilder.IndexMethod(i.Method))
+	}
+	for _, c := range i.Columns {
+		col := sqlbuilder.IndexColumn(c.Expr).OpClass(c.OpClass)
+		if c.Desc {
+			col.Desc()
+		}
+		switch c.Nulls {
+		case "FIRST":
+			col.NullsFirst()
+		case "LAST":
+			col.NullsLast()
+		}
+		b.Columns(col)
+	}
+	if i.Where != "" {
+		b.WhereExpr(i.Where)
+	}
+	return b
+}
@@ -0,0 +1,221 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Benehiko/sqlbuilder"
+	"github.com/Benehiko/sqlbuilder/migrate"
+)
+
+const dump = `
+CREATE TABLE roles (id BIGINT PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE users (
+	id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	name VARCHAR(100),
+	score INT DEFAULT 0,
+	legacy TEXT,
+	role_id BIGINT CONSTRAINT users_role_fk REFERENCES roles (id)
+);
+CREATE INDEX users_name_idx ON users (name);
+CREATE TABLE audit (id BIGINT PRIMARY KEY);
+`
+
+func target(t *testing.T) *Schema {
+	s, err := FromStatements(sqlbuilder.Postgres,
+		sqlbuilder.CreateTable("teams").Columns(
+			sqlbuilder.Column("id", sqlbuilder.BigInt).PrimaryKey(),
+		),
+		sqlbuilder.CreateTable("members").Columns(
+			sqlbuilder.Column("id", sqlbuilder.BigInt).Identity().PrimaryKey(),
+			sqlbuilder.Column("full_name", sqlbuilder.Varchar(100)).NotNull(),
+			sqlbuilder.Column("score", sqlbuilder.BigInt).Default("0"),
+			sqlbuilder.Column("team_id", sqlbuilder.BigInt).References("teams", "id"),
+			sqlbuilder.Column("deleted_at", sqlbuilder.Timestamp),
+		),
+		sqlbuilder.CreateTable("roles").Columns(
+			sqlbuilder.Column("id", sqlbuilder.BigInt).PrimaryKey(),
+			sqlbuilder.Column("name", sqlbuilder.Text).NotNull(),
+		),
+		sqlbuilder.CreateIndex("members_name_idx").On("members", "lower(full_name)").Where("deleted_at", sqlbuilder.IsNull),
+	)
+	require.NoError(t, err)
+	return s
+}
+
+func TestCompare(t *testing.T) {
+	from, err := ParseDDL(dump)
+	require.NoError(t, err)
+	to := target(t)
+
+	t.Run("case=changes in order", func(t *testing.T) {
+		d, err := Compare(from, to, RenameTable("users", "members"), RenameColumn("members", "name", "full_name"))
+		require.NoError(t, err)
+
+		stmts, err := d.Statements(sqlbuilder.Postgres)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ALTER TABLE users RENAME TO members",
+			"DROP INDEX users_name_idx",
+			"CREATE TABLE teams (id BIGINT NOT NULL PRIMARY KEY)",
+			"ALTER TABLE members RENAME COLUMN name TO full_name",
+			"ALTER TABLE members ALTER COLUMN full_name SET NOT NULL",
+			"ALTER TABLE members ALTER COLUMN score TYPE BIGINT",
+			"ALTER TABLE members ADD COLUMN team_id BIGINT",
+			"ALTER TABLE members ADD COLUMN deleted_at TIMESTAMPTZ",
+			"ALTER TABLE members DROP COLUMN legacy",
+			"ALTER TABLE members DROP COLUMN role_id",
+			"ALTER TABLE members ADD FOREIGN KEY (team_id) REFERENCES teams (id)",
+			"CREATE INDEX members_name_idx ON members ((lower(full_name))) WHERE deleted_at IS NULL",
+			"DROP TABLE audit",
+		}, stmts)
+
+		var destructive []string
+		for _, c := range d.Destructive() {
+			destructive = append(destructive, c.Description)
+		}
+		require.Equal(t, []string{
+			"change type of column members.score from INT to BIGINT",
+			"drop column members.legacy",
+			"drop column members.role_id",
+			"drop table audit",
+		}, destructive)
+	})
+
+	t.Run("case=without hints", func(t *testing.T) {
+		d, err := Compare(from, to)
+		require.NoError(t, err)
+		stmts, err := d.Statements(sqlbuilder.Postgres)
+		require.NoError(t, err)
+		require.Contains(t, stmts, "DROP TABLE users")
+		require.Contains(t, stmts, "CREATE TABLE members (id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY, "+
+			"full_name VARCHAR(100) NOT NULL, score BIGINT DEFAULT 0, team_id BIGINT, deleted_at TIMESTAMPTZ, "+
+			"FOREIGN KEY (team_id) REFERENCES teams (id))")
+		require.Less(t, slices.Index(stmts, "CREATE TABLE teams (id BIGINT NOT NULL PRIMARY KEY)"), slices.Index(stmts, "CREATE INDEX members_name_idx ON members ((lower(full_name))) WHERE deleted_at IS NULL"))
+	})
+
+	t.Run("case=identical", func(t *testing.T) {
+		d, err := Compare(to, target(t))
+		require.NoError(t, err)
+		require.Empty(t, d.Changes)
+	})
+
+	t.Run("case=reverse", func(t *testing.T) {
+		d, err := Compare(from, to, RenameTable("users", "members"), RenameColumn("members", "name", "full_name"))
+		require.NoError(t, err)
+		r, err := d.Reverse()
+		require.NoError(t, err)
+
+		stmts, err := r.Statements(sqlbuilder.Postgres)
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE members RENAME TO users", stmts[0])
+		require.Contains(t, stmts, "ALTER TABLE users RENAME COLUMN full_name TO name")
+		require.Contains(t, stmts, "ALTER TABLE users ADD CONSTRAINT users_role_fk FOREIGN KEY (role_id) REFERENCES roles (id)")
+		require.Contains(t, stmts, "CREATE TABLE audit (id BIGINT NOT NULL PRIMARY KEY)")
+		require.Equal(t, "DROP TABLE teams", stmts[len(stmts)-1])
+	})
+
+	t.Run("case=invalid hints", func(t *testing.T) {
+		_, err := Compare(from, to, RenameTable("nope", "members"))
+		require.ErrorIs(t, err, ErrInvalidHint)
+		_, err = Compare(from, to, RenameTable("users", "members"), RenameColumn("members", "nope", "full_name"))
+		require.ErrorIs(t, err, ErrInvalidHint)
+	})
+
+	t.Run("case=pg_dump", func(t *testing.T) {
+		current, err := ParseDDL(`
+SET statement_timeout = 0;
+CREATE FUNCTION public.touch() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	NEW.updated_at := now(); RETURN NEW;
+END;
+$$;
+CREATE TABLE public.roles (
+    id bigint NOT NULL,
+    name text NOT NULL
+);
+CREATE TABLE public.users (
+    id bigint NOT NULL,
+    email character varying(255) NOT NULL,
+    role_id bigint,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+ALTER TABLE public.users OWNER TO app;
+ALTER TABLE ONLY public.roles
+    ADD CONSTRAINT roles_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+CREATE INDEX users_email_idx ON public.users USING btree (email);
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_role_id_fkey FOREIGN KEY (role_id) REFERENCES public.roles(id) ON DELETE CASCADE;
+`)
+		require.NoError(t, err)
+		users := current.Table("users")
+		require.NotNil(t, users)
+		require.Equal(t, []string{"id"}, users.PrimaryKey)
+		require.Equal(t, []ForeignKey{{Name: "users_role_id_fkey", Columns: []string{"role_id"}, RefTable: "roles", RefColumns: []string{"id"}, OnDelete: "CASCADE"}}, users.ForeignKeys)
+
+		target, err := FromStatements(sqlbuilder.Postgres,
+			sqlbuilder.CreateTable("roles").Columns(
+				sqlbuilder.Column("id", sqlbuilder.BigInt).PrimaryKey(),
+				sqlbuilder.Column("name", sqlbuilder.Text).NotNull(),
+			),
+			sqlbuilder.CreateTable("users").Columns(
+				sqlbuilder.Column("id", sqlbuilder.BigInt).PrimaryKey(),
+				sqlbuilder.Column("email", sqlbuilder.Varchar(255)).NotNull(),
+				sqlbuilder.Column("role_id", sqlbuilder.BigInt).References("roles", "id", sqlbuilder.Cascade),
+				sqlbuilder.Column("created_at", sqlbuilder.Timestamp).NotNull().Default("NOW()"),
+			),
+			sqlbuilder.CreateIndex("users_email_idx").On("users", "email"),
+		)
+		require.NoError(t, err)
+
+		d, err := Compare(current, target)
+		require.NoError(t, err)
+		require.Empty(t, d.Changes)
+	})
+
+	t.Run("case=unnamed foreign key", func(t *testing.T) {
+		a, err := ParseDDL(`CREATE TABLE users (id INT, role_id INT REFERENCES roles (id))`)
+		require.NoError(t, err)
+		b, err := ParseDDL(`CREATE TABLE users (id INT, role_id INT)`)
+		require.NoError(t, err)
+		_, err = Compare(a, b)
+		require.ErrorIs(t, err, ErrUnnamedConstraint)
+	})
+}
+
+func TestDiffMigration(t *testing.T) {
+	from, err := ParseDDL(dump)
+	require.NoError(t, err)
+	d, err := Compare(from, target(t), RenameTable("users", "members"), RenameColumn("members", "name", "full_name"))
+	require.NoError(t, err)
+
+	t.Run("case=migration", func(t *testing.T) {
+		m, err := d.Migration(3, "members")
+		require.NoError(t, err)
+		require.EqualValues(t, 3, m.Version)
+		require.Len(t, m.Up, len(d.Changes))
+		require.NotEmpty(t, m.Down)
+	})
+
+	t.Run("case=files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, d.WriteFiles(dir, 3, "rename users", sqlbuilder.Postgres))
+
+		up, err := os.ReadFile(filepath.Join(dir, "3_rename_users.up.sql"))
+		require.NoError(t, err)
+		require.Contains(t, string(up), "-- destructive: drop table audit\n")
+		require.Contains(t, string(up), "ALTER TABLE users RENAME TO members;\n")
+
+		migrations, err := migrate.FromFS(os.DirFS(dir), ".")
+		require.NoError(t, err)
+		require.Len(t, migrations, 1)
+		require.Equal(t, "rename_users", migrations[0].Name)
+		require.Len(t, migrations[0].Down, 1)
+	})
+}
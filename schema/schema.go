@@ -0,0 +1,99 @@
+// Package schema describes database tables independently of the statements
+// that create them.
+package schema
+
+type (
+	Schema struct {
+		Tables []*Table
+	}
+	Table struct {
+		Name        string
+		Columns     []*Column
+		PrimaryKey  []string
+		Unique      [][]string
+		ForeignKeys []ForeignKey
+		Checks      []string
+		Indexes     []*Index
+	}
+	Column struct {
+		Name string
+		// Type is the type of the column as written in the DDL,
+		// e.g. VARCHAR(255).
+		Type    string
+		NotNull bool
+		// Default is the default expression of the column, empty when
+		// there is none.
+		Default string
+		// Identity is set for auto incremented columns, e.g. SERIAL,
+		// GENERATED ... AS IDENTITY or AUTO_INCREMENT.
+		Identity bool
+	}
+	ForeignKey struct {
+		// Name is the name of the constraint, empty when it isn't named.
+		Name       string
+		Columns    []string
+		RefTable   string
+		RefColumns []string
+		OnDelete   string
+		OnUpdate   string
+	}
+	Index struct {
+		Name   string
+		Unique bool
+		// Method is the access method of the index, e.g. gin, empty for
+		// the default one.
+		Method  string
+		Columns []IndexColumn
+		Include []string
+		// Where is the predicate of a partial index as written in the DDL.
+		Where string
+	}
+	IndexColumn struct {
+		// Expr is a column name or an expression, without its parentheses.
+		Expr string
+		Desc bool
+		// Nulls is FIRST or LAST when the position of NULLs is given.
+		Nulls   string
+		OpClass string
+	}
+)
+
+// Table returns the table with the given name, or nil.
+func (s *Schema) Table(name string) *Table {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Column returns the column with the given name, or nil.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// IsPrimaryKey reports whether the column is part of the primary key.
+func (t *Table) IsPrimaryKey(column string) bool {
+	for _, c := range t.PrimaryKey {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Index returns the index with the given name, or nil.
+func (t *Table) Index(name string) *Index {
+	for _, i := range t.Indexes {
+		if i.Name == name {
+			return i
+		}
+	}
+	return nil
+}
@@ -1,12 +1,28 @@
 package sqlbuilder
 
+import (
+	"maps"
+	"slices"
+)
+
 type (
 	SelectFromQuery interface {
 		Where[SelectFromQuery]
 		Joins
 		Alias[SelectFromQuery]
 		Order[SelectFromQuery]
-		SQL() string
+		// WithDeleted includes the rows soft deleted according to a
+		// SoftDeleteScope.
+		WithDeleted() SelectFromQuery
+		// OnlyDeleted selects only the rows soft deleted according to a
+		// SoftDeleteScope.
+		OnlyDeleted() SelectFromQuery
+		ThenBy(orderBy OrderBy, columns ...string) SelectFromQuery
+		Filter(conds ...*WhereCondition) SelectFromQuery
+		// When calls f with the select when cond is true.
+		When(cond bool, f func(q SelectFromQuery)) SelectFromQuery
+		Clone() SelectFromQuery
+		Statement
 	}
 	SelectQuery interface {
 		Select(columns ...string) FromQuery[SelectFromQuery]
@@ -20,10 +36,19 @@ type SelectBuilder struct {
 	columns []string
 	orderBy *Sort
 	joins   []*Join
-	pos     int
+	// qualify prefixes the columns with the alias of the table.
+	qualify bool
+	deleted deletedRows
+	err     error
+	tags    Tags
 	*WhereBuilder[SelectFromQuery]
 }
 
+// GetPosition implements queryHelper
+func (s *SelectBuilder) GetPosition() *int {
+	return new(int)
+}
+
 // GetAlias implements queryHelper
 func (s *SelectBuilder) GetAlias() string {
 	return s.alias
@@ -31,7 +56,14 @@ func (s *SelectBuilder) GetAlias() string {
 
 // GetColumns implements queryHelper
 func (s *SelectBuilder) GetColumns() []string {
-	return s.columns
+	if !s.qualify || s.alias == "" {
+		return s.columns
+	}
+	columns := make([]string, len(s.columns))
+	for i, c := range s.columns {
+		columns[i] = s.alias + "." + c
+	}
+	return columns
 }
 
 // GetJoins implements queryHelper
@@ -49,11 +81,6 @@ func (s *SelectBuilder) GetParent() any {
 	return s.parent
 }
 
-// GetPosition implements queryHelper
-func (s *SelectBuilder) GetPosition() *int {
-	return &s.pos
-}
-
 // GetReturning implements queryHelper
 func (s *SelectBuilder) GetReturning() []string {
 	return nil
@@ -93,6 +120,16 @@ func (s *SelectBuilder) As(alias string) SelectFromQuery {
 	return s
 }
 
+func (s *SelectBuilder) WithDeleted() SelectFromQuery {
+	s.deleted = withDeleted
+	return s
+}
+
+func (s *SelectBuilder) OnlyDeleted() SelectFromQuery {
+	s.deleted = onlyDeleted
+	return s
+}
+
 func (s *SelectBuilder) Where(column string, operator Operator) WhereOptions[SelectFromQuery] {
 	s.WhereBuilder = &WhereBuilder[SelectFromQuery]{
 		where: &WhereCondition{
@@ -101,6 +138,21 @@ func (s *SelectBuilder) Where(column string, operator Operator) WhereOptions[Sel
 		},
 		parent: s,
 	}
+	return s.WhereBuilder
+}
+
+func (s *SelectBuilder) WhereOptional(column string, operator Operator, value any) WhereOptions[SelectFromQuery] {
+	s.WhereBuilder = &WhereBuilder[SelectFromQuery]{
+		where:  optional(column, operator, value),
+		parent: s,
+	}
+	return s.WhereBuilder
+}
+
+func (s *SelectBuilder) When(cond bool, f func(q SelectFromQuery)) SelectFromQuery {
+	if cond {
+		f(s)
+	}
 	return s
 }
 
@@ -112,6 +164,41 @@ func (s *SelectBuilder) OrderBy(orderBy OrderBy, columns ...string) SelectFromQu
 	return s
 }
 
+// ThenBy adds a sort term after those of OrderBy, e.g. to sort by one
+// column descending and another ascending. It is the same as OrderBy when
+// the select isn't sorted yet.
+func (s *SelectBuilder) ThenBy(orderBy OrderBy, columns ...string) SelectFromQuery {
+	term := &Sort{
+		columns: columns,
+		orderBy: orderBy,
+	}
+	if s.orderBy == nil {
+		s.orderBy = term
+		return s
+	}
+	last := s.orderBy
+	for last.next != nil {
+		last = last.next
+	}
+	last.next = term
+	return s
+}
+
+// Filter adds the conditions, built with Cond, AllOf and AnyOf, to the where
+// chain of the select. They have to hold in addition to the where chain,
+// which is put in parentheses when it contains OR. Call Filter after Where,
+// which starts a new where chain.
+func (s *SelectBuilder) Filter(conds ...*WhereCondition) SelectFromQuery {
+	where := s.GetWhere()
+	for i := len(conds) - 1; i >= 0; i-- {
+		where = and(conds[i].Clone(), where)
+	}
+	if where != nil {
+		s.WhereBuilder = &WhereBuilder[SelectFromQuery]{parent: s, where: where}
+	}
+	return s
+}
+
 func (s *SelectBuilder) InnerJoin(table string) AliasOrJoinOn {
 	if s.joins == nil {
 		s.joins = make([]*Join, 0)
@@ -165,3 +252,85 @@ func (s *SelectBuilder) RightJoin(table string) AliasOrJoinOn {
 func (s *SelectBuilder) SQL() string {
 	return SQL(s)
 }
+
+func (s *SelectBuilder) Pretty() string {
+	return Pretty(s)
+}
+
+func (s *SelectBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(s, opts...)
+}
+
+func (s *SelectBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(s, opts...)
+}
+
+func (s *SelectBuilder) Tag(key, value string) Statement {
+	s.tags.set(key, value)
+	return s
+}
+
+func (s *SelectBuilder) GetTags() Tags {
+	return s.tags
+}
+
+func (s *SelectBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(s, opts...)
+}
+
+func (s *SelectBuilder) Err() error {
+	return Err(s)
+}
+
+func (s *SelectBuilder) Args() []any {
+	return Args(s)
+}
+
+func (s *SelectBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(s, opts...)
+}
+
+// Clone returns a deep copy of the query, including its joins and where
+// chain. A select belonging to an INSERT ... SELECT is cloned together with
+// its insert.
+func (s *SelectBuilder) Clone() SelectFromQuery {
+	return s.clone()
+}
+
+func (s *SelectBuilder) clone() *SelectBuilder {
+	if p, ok := s.parent.(*InsertBuilder); ok && p.s == s {
+		return p.clone().s.(*SelectBuilder)
+	}
+	return s.cloneWithParent(s.parent)
+}
+
+func (s *SelectBuilder) cloneWithParent(parent any) *SelectBuilder {
+	c := &SelectBuilder{
+		parent:  parent,
+		table:   s.table,
+		alias:   s.alias,
+		columns: slices.Clone(s.columns),
+		qualify: s.qualify,
+		deleted: s.deleted,
+		err:     s.err,
+		tags:    maps.Clone(s.tags),
+	}
+	c.orderBy = s.orderBy.clone()
+	if s.joins != nil {
+		c.joins = make([]*Join, len(s.joins))
+		for i, j := range s.joins {
+			c.joins[i] = j.clone(c)
+		}
+	}
+	if s.WhereBuilder != nil {
+		c.WhereBuilder = &WhereBuilder[SelectFromQuery]{
+			parent: c,
+			where:  s.where.Clone(),
+		}
+	}
+	return c
+}
+
+func (s *SelectBuilder) cloneWhere() *WhereBuilder[SelectFromQuery] {
+	return s.clone().WhereBuilder
+}
@@ -0,0 +1,76 @@
+package sqlbuilder
+
+// SoftDeleteScope maps the tables whose rows are soft deleted to the column
+// holding the time of deletion, e.g. SoftDeleteScope{"users": "deleted_at"}.
+// Register its Hook to hide the deleted rows of those tables from SELECT and
+// UPDATE and to soft delete their rows instead of deleting them.
+type SoftDeleteScope map[string]string
+
+// deletedRows selects which soft deleted rows a SELECT returns.
+type deletedRows int
+
+const (
+	excludeDeleted deletedRows = iota
+	withDeleted
+	onlyDeleted
+)
+
+// Hook returns a Hook adding column IS NULL to the where chain of every
+// SELECT and UPDATE of a scoped table and to the ON condition of every
+// joined scoped table, and rewriting every DELETE from a scoped table into
+// an UPDATE setting the column to the current time with the same where
+// chain, which skips the rows already deleted.
+//
+// A SELECT or UPDATE made WithDeleted is left as it is, a SELECT made
+// OnlyDeleted gets column IS NOT NULL instead, while its joined tables still
+// exclude their deleted rows. A DELETE made HardDelete is left as it is.
+func (sd SoftDeleteScope) Hook() Hook {
+	return func(s *Structure) error {
+		switch b := s.q.(type) {
+		case *SelectBuilder:
+			if b.deleted == withDeleted {
+				return nil
+			}
+			for _, j := range b.joins {
+				if column, ok := sd[j.GetTable()]; ok {
+					j.Filter(qualify(j.GetAlias(), j.GetTable(), column), IsNull)
+				}
+			}
+			column, scoped := sd[b.table]
+			if !scoped {
+				return nil
+			}
+			if len(b.joins) != 0 {
+				column = qualify(b.alias, b.table, column)
+			}
+			if b.deleted == onlyDeleted {
+				s.Filter(column, IsNotNull)
+			} else {
+				s.Filter(column, IsNull)
+			}
+
+		case *DeleteBuilder:
+			column, scoped := sd[b.table]
+			if !scoped || b.hard {
+				return nil
+			}
+			ub := &UpdateBuilder{
+				table:   b.table,
+				columns: []string{column},
+				exprs:   map[string]string{column: s.Dialect.now},
+				tags:    b.tags,
+			}
+			if b.WhereBuilder != nil {
+				ub.WhereBuilder = &WhereBuilder[UpdateReturningQuery]{parent: ub, where: b.where}
+			}
+			s.Kind, s.q = UpdateKind, ub
+			s.Filter(column, IsNull)
+
+		case *UpdateBuilder:
+			if column, scoped := sd[b.table]; scoped && !b.withDeleted {
+				s.Filter(column, IsNull)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package sqlbuilder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDeleteScope(t *testing.T) {
+	hooks := &Hooks{}
+	hooks.Register(SoftDeleteScope{"users": "deleted_at", "roles": "removed_at"}.Hook())
+	build := func(t *testing.T, q Statement, opts ...RenderOption) (string, []any) {
+		var bound []any
+		s, err := q.Build(append(opts, WithHooks(hooks), CaptureArgs(&bound))...)
+		require.NoError(t, err)
+		return s, bound
+	}
+
+	t.Run("case=select", func(t *testing.T) {
+		s, _ := build(t, Select().From("users").Where("name", Equals).Or("email", Equals))
+		require.Equal(t, "SELECT * FROM users WHERE deleted_at IS NULL AND (name = $1 OR email = $2)", s)
+
+		s, _ = build(t, Select("u.id").From("users").As("u").LeftJoin("roles").On("roles.id", "u.role_id"))
+		require.Equal(t, "SELECT u.id FROM users AS u LEFT JOIN roles ON roles.id = u.role_id AND roles.removed_at IS NULL "+
+			"WHERE u.deleted_at IS NULL", s)
+
+		s, _ = build(t, Select().From("teams"))
+		require.Equal(t, "SELECT * FROM teams", s)
+	})
+
+	t.Run("case=overrides", func(t *testing.T) {
+		s, _ := build(t, Select().From("users").WithDeleted().InnerJoin("roles").As("r").On("r.id", "users.role_id"))
+		require.Equal(t, "SELECT * FROM users INNER JOIN roles AS r ON r.id = users.role_id", s)
+
+		s, _ = build(t, Select().From("users").OnlyDeleted().InnerJoin("roles").As("r").On("r.id", "users.role_id"))
+		require.Equal(t, "SELECT * FROM users INNER JOIN roles AS r ON r.id = users.role_id AND r.removed_at IS NULL "+
+			"WHERE users.deleted_at IS NOT NULL", s)
+
+		q := Select().From("users").OnlyDeleted()
+		s, _ = build(t, q.Clone())
+		require.Equal(t, "SELECT * FROM users WHERE deleted_at IS NOT NULL", s)
+		require.Equal(t, "SELECT * FROM users", q.SQL())
+	})
+
+	t.Run("case=delete", func(t *testing.T) {
+		s, args := build(t, Delete().From("users").Where("id", Equals).Or("name", In(2)), WithArgs(1, "a", "b"))
+		require.Equal(t, "UPDATE users SET deleted_at = now() WHERE deleted_at IS NULL AND (id = $1 OR name IN ($2, $3))", s)
+		require.Equal(t, []any{1, "a", "b"}, args)
+
+		s, _ = build(t, Delete().From("users"), WithDialect(MySQL))
+		require.Equal(t, "UPDATE users SET deleted_at = NOW(6) WHERE deleted_at IS NULL", s)
+
+		s, _ = build(t, Delete().From("roles").Where("id", Equals), WithDialect(SQLServer))
+		require.Equal(t, "UPDATE roles SET removed_at = SYSDATETIMEOFFSET() WHERE removed_at IS NULL AND id = @p1", s)
+
+		s, _ = build(t, Delete().From("users").HardDelete().Where("id", Equals))
+		require.Equal(t, "DELETE FROM users WHERE id = $1", s)
+
+		s, _ = build(t, Delete().From("teams").Where("id", Equals))
+		require.Equal(t, "DELETE FROM teams WHERE id = $1", s)
+	})
+
+	t.Run("case=update", func(t *testing.T) {
+		s, args := build(t, Update("users").Set("name").Where("id", Equals).Parent().(Statement), WithArgs("foo", 1))
+		require.Equal(t, "UPDATE users SET name = $1 WHERE deleted_at IS NULL AND id = $2", s)
+		require.Equal(t, []any{"foo", 1}, args)
+
+		s, _ = build(t, Update("users").Set("deleted_at").WithDeleted().Where("id", Equals).Parent().(Statement))
+		require.Equal(t, "UPDATE users SET deleted_at = $1 WHERE id = $2", s)
+
+		s, _ = build(t, Update("teams").Set("name"))
+		require.Equal(t, "UPDATE teams SET name = $1", s)
+	})
+
+	t.Run("case=with tenant scope", func(t *testing.T) {
+		hooks := &Hooks{}
+		hooks.Register(SoftDeleteScope{"users": "deleted_at"}.Hook())
+		hooks.Register(TenantScope{"users": "tenant_id"}.Hook())
+		ctx := ContextWithTenant(context.Background(), 42)
+
+		var args []any
+		s, err := Delete().From("users").Where("id", Equals).Build(WithHooks(hooks), WithContext(ctx), WithArgs(1), CaptureArgs(&args))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET deleted_at = now() WHERE tenant_id = $1 AND deleted_at IS NULL AND id = $2", s)
+		require.Equal(t, []any{42, 1}, args)
+	})
+}
@@ -1,8 +1,9 @@
 package sqlbuilder
 
 import (
+	"context"
 	"fmt"
-	"strconv"
+	"slices"
 	"strings"
 )
 
@@ -20,6 +21,29 @@ type (
 		On(column string, joinColumn string) SelectFromQuery
 	}
 
+	Statement interface {
+		SQL() string
+		// Pretty renders the same statement as SQL laid out over several
+		// lines in the DefaultFormat.
+		Pretty() string
+		// Interpolate renders the statement with its bound values inlined
+		// for debugging, it must not be executed.
+		Interpolate(opts ...RenderOption) string
+		// Fingerprint identifies the shape of the statement regardless of
+		// its values.
+		Fingerprint(opts ...RenderOption) Fingerprint
+		// Tag adds a key/value pair to the comment rendered after the
+		// statement.
+		Tag(key, value string) Statement
+		Build(opts ...RenderOption) (string, error)
+		Err() error
+		// Args returns the values bound to the statement in placeholder
+		// order.
+		Args() []any
+		// Compile renders the statement once into a Template.
+		Compile(opts ...RenderOption) (*Template, error)
+	}
+
 	Query interface {
 		SelectQuery
 		InsertQuery
@@ -49,12 +73,24 @@ const (
 type Sort struct {
 	columns []string
 	orderBy OrderBy
+	// next holds the sort terms added with ThenBy.
+	next *Sort
 }
 
-func Select(columns ...string) FromQuery[SelectFromQuery] {
-	sb := &SelectBuilder{
-		pos: 1,
+// clone deep copies the sort and the terms after it.
+func (o *Sort) clone() *Sort {
+	if o == nil {
+		return nil
 	}
+	return &Sort{
+		columns: slices.Clone(o.columns),
+		orderBy: o.orderBy,
+		next:    o.next.clone(),
+	}
+}
+
+func Select(columns ...string) FromQuery[SelectFromQuery] {
+	sb := &SelectBuilder{}
 	sb.Select(columns...)
 	return sb
 }
@@ -90,50 +126,79 @@ func WhereOperator[T Operator](next *WhereCondition, operator T, column string,
 }
 
 func WhereSQLHelper(current *WhereCondition, pos *int, sb *strings.Builder) {
+	rc := &renderContext{pos: *pos, dialect: Postgres}
+	whereSQL(rc, current)
+	sb.WriteString(rc.sb.String())
+	*pos = rc.pos
+}
+
+func whereSQL(rc *renderContext, current *WhereCondition) {
+	sb := &rc.sb
+	conditionSQL(rc, current)
+
+	if current.next != nil {
+		// Right align the operator with WHERE so the conditions line up.
+		op := string(current.nextOp)
+		rc.newline(strings.Repeat(" ", max(0, len("WHERE")-len(op))), " ")
+		rc.keyword(op)
+		sb.WriteString(" ")
+		whereSQL(rc, current.next)
+	}
+}
+
+// conditionSQL renders the condition without the conditions chained after
+// it.
+func conditionSQL(rc *renderContext, current *WhereCondition) {
+	sb := &rc.sb
+	if current.group != nil {
+		sb.WriteString("(")
+		whereSQL(rc, current.group)
+		sb.WriteString(")")
+		return
+	}
 	sb.WriteString(current.ColumnA)
 
 	switch op := any(current.Op.get()).(type) {
 	case BasicOperator:
 		sb.WriteString(" ")
-		sb.WriteString(string(op))
-		sb.WriteString(" ")
-		sb.WriteString("$" + strconv.FormatInt(int64(*pos), 10))
-		*pos++
+		rc.keyword(string(op))
+		switch {
+		case op.unary():
+		case current.ColumnB != "":
+			sb.WriteString(" ")
+			sb.WriteString(current.ColumnB)
+		default:
+			sb.WriteString(" ")
+			rc.bind(current.ColumnA, current.values, 0)
+		}
 	case SpecialOperator:
 		count, o := op()
 		sb.WriteString(" ")
-		sb.WriteString(o)
-		sb.WriteString(" ")
-
-		if count == 0 {
-			sb.WriteString("()")
-			return
+		rc.keyword(o)
+		sb.WriteString(" (")
+		if rc.normalize {
+			// Lists of any length have the same shape.
+			count = min(count, 1)
 		}
-
-		sb.WriteString("(")
 		for i := 0; i < count; i++ {
 			if i > 0 {
 				sb.WriteString(", ")
 			}
-			sb.WriteString("$" + strconv.FormatInt(int64(*pos), 10))
-			*pos += 1
+			rc.bind(current.ColumnA, current.values, i)
 		}
 		sb.WriteString(")")
-
-	}
-
-	if current.next != nil {
-		sb.WriteString(" " + string(current.nextOp) + " ")
-		WhereSQLHelper(current.next, pos, sb)
 	}
 }
 
+// WhereSQL writes the where chain of q with Postgres placeholders numbered
+// from 1.
 func WhereSQL[T queryHelper](q T, sb *strings.Builder) {
 	if q.GetWhere() == nil {
 		return
 	}
+	pos := 1
 	sb.WriteString(" WHERE ")
-	WhereSQLHelper(q.GetWhere(), q.GetPosition(), sb)
+	WhereSQLHelper(q.GetWhere(), &pos, sb)
 }
 
 func ReturningSQL[T queryHelper](q T, sb *strings.Builder) {
@@ -144,106 +209,438 @@ func ReturningSQL[T queryHelper](q T, sb *strings.Builder) {
 }
 
 func OrderBySQL[T queryHelper](q T, sb *strings.Builder) {
-	if q.GetOrderBy() != nil {
-		sb.WriteString(" ORDER BY ")
-		sb.WriteString(strings.TrimSpace(strings.Join(q.GetOrderBy().columns, ", ")))
+	for o := q.GetOrderBy(); o != nil; o = o.next {
+		if o == q.GetOrderBy() {
+			sb.WriteString(" ORDER BY ")
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strings.TrimSpace(strings.Join(o.columns, ", ")))
 		sb.WriteString(" ")
-		sb.WriteString(string(q.GetOrderBy().orderBy))
+		sb.WriteString(string(o.orderBy))
 	}
 }
 
-func SQL[T any](q T) string {
-	switch q := any(q).(type) {
-	case queryHelper:
-		var sb strings.Builder
-		pos := q.GetPosition()
+// renderContext holds the state of a single render. Builders never store
+// placeholder positions themselves, so rendering the same builder any number
+// of times, from any number of goroutines, yields the same statement.
+type renderContext struct {
+	sb      strings.Builder
+	pos     int
+	dialect *Dialect
+	args    []any
+	// inline writes bound values as literals instead of placeholders.
+	inline bool
+	// format lays the statement out over several lines, see Pretty.
+	format *Format
+	// interpolate writes the bound values as literals and keeps the
+	// placeholders of the values which are not bound, see Interpolate.
+	interpolate bool
+	redact      Redactor
+	// normalize writes every value as a single ? and collapses lists of
+	// values, see FingerprintOf.
+	normalize bool
+	// ctx carries tags and is passed to hooks, see WithContext.
+	ctx      context.Context
+	omitTags bool
+	hooks    *Hooks
+	// capture receives the bound values, see CaptureArgs.
+	capture *[]any
+	// extra are bound to the placeholders without a value, see WithArgs.
+	extra []any
+	// params are the values of the named parameters, see Bind, and named
+	// records the position of the parameters rendered so far.
+	params map[string]any
+	named  map[string]int
+	err    error
+	// holes receives the index in args of every placeholder without a
+	// value, see Compile.
+	holes *[]int
+}
 
-		if *pos == 0 {
-			*pos = 1
+// RenderOption configures a single call to Build.
+type RenderOption func(rc *renderContext)
+
+// WithDialect renders the statement for the given dialect. Statements are
+// rendered for Postgres by default.
+func WithDialect(d *Dialect) RenderOption {
+	return func(rc *renderContext) {
+		rc.dialect = d
+	}
+}
+
+func newRenderContext(opts ...RenderOption) *renderContext {
+	rc := &renderContext{pos: 1, dialect: Postgres, ctx: context.Background()}
+	for _, opt := range opts {
+		opt(rc)
+	}
+	return rc
+}
+
+func (rc *renderContext) placeholder() {
+	if rc.normalize {
+		rc.sb.WriteString("?")
+	} else {
+		rc.sb.WriteString(rc.dialect.placeholder(rc.pos))
+	}
+	rc.pos++
+}
+
+// bind writes a placeholder for the i-th of the values bound to column and
+// records the value as an argument when one is bound.
+func (rc *renderContext) bind(column string, values []any, i int) {
+	bound := i < len(values)
+	var v any
+	switch {
+	case bound:
+		v = values[i]
+	case len(rc.extra) != 0 && !rc.inline && !rc.normalize:
+		v, rc.extra, bound = rc.extra[0], rc.extra[1:], true
+	}
+	p, isParam := v.(Param)
+	switch {
+	case rc.normalize:
+		rc.placeholder()
+	case rc.inline:
+		if isParam && rc.err == nil {
+			rc.err = fmt.Errorf("%w: parameter %s can't be inlined", ErrUnsupportedStatement, string(p))
+		}
+		rc.sb.WriteString(rc.dialect.literal(v))
+	case bound && isParam:
+		rc.param(string(p))
+	case rc.interpolate && bound:
+		if rc.redact != nil {
+			v = rc.redact(column, v)
 		}
+		rc.sb.WriteString(rc.dialect.literal(v))
+		rc.pos++
+	default:
+		switch {
+		case bound:
+			rc.args = append(rc.args, v)
+		case rc.holes != nil:
+			*rc.holes = append(*rc.holes, len(rc.args))
+			rc.args = append(rc.args, nil)
+		}
+		rc.placeholder()
+	}
+}
 
-		switch any(q).(type) {
-		case SelectQuery:
+func (rc *renderContext) where(q queryHelper) {
+	if q.GetWhere() == nil {
+		return
+	}
+	rc.clause("WHERE")
+	rc.sb.WriteString(" ")
+	whereSQL(rc, q.GetWhere())
+}
 
-			switch p := q.GetParent().(type) {
-			case InsertIntoQuery:
-				sb.WriteString(p.SQL())
-				sb.WriteString(" ")
-			}
+func (rc *renderContext) returning(q queryHelper) {
+	if len(q.GetReturning()) != 0 {
+		rc.clause("RETURNING")
+		rc.list(" ", q.GetReturning())
+	}
+}
 
-			sb.WriteString("SELECT")
-			if len(q.GetColumns()) == 0 {
-				sb.WriteString(" * ")
-			} else {
-				sb.WriteString(" ")
-				for i, c := range q.GetColumns() {
-					if i > 0 {
-						sb.WriteString(", ")
-					}
-					sb.WriteString(c)
-				}
-			}
-			sb.WriteString(" FROM ")
-			sb.WriteString(q.GetTable())
+func (rc *renderContext) orderBy(q queryHelper) {
+	o := q.GetOrderBy()
+	if o == nil {
+		return
+	}
+	// The direction of each term follows its last column.
+	var items []string
+	for ; o != nil; o = o.next {
+		items = append(items, o.columns...)
+		items[len(items)-1] += rc.fragment(func(rc *renderContext) {
+			rc.sb.WriteString(" ")
+			rc.keyword(string(o.orderBy))
+		})
+	}
+	rc.clause("ORDER BY")
+	rc.list(" ", items)
+}
 
-			if alias := q.GetAlias(); alias != "" {
-				sb.WriteString(" AS ")
-				sb.WriteString(alias)
-			}
+// fragment renders f on its own and returns what it wrote, keeping the
+// placeholder positions and arguments of the statement.
+func (rc *renderContext) fragment(f func(rc *renderContext)) string {
+	sub := *rc
+	sub.sb = strings.Builder{}
+	f(&sub)
+	rc.pos, rc.args, rc.extra = sub.pos, sub.args, sub.extra
+	rc.named, rc.err = sub.named, sub.err
+	return sub.sb.String()
+}
 
-			if q.GetJoins() != nil {
-				for _, join := range q.GetJoins() {
-					sb.WriteString(join.SQL())
-				}
+// selfRenderer is implemented by statements that render and validate
+// themselves, such as the DDL builders.
+type selfRenderer interface {
+	render(rc *renderContext)
+	validate(d *Dialect) error
+}
+
+// statement returns the builder rendering q: the SELECT of an INSERT ...
+// SELECT, which renders its INSERT before itself, or q.
+func statement(q any) any {
+	if ib, ok := q.(*InsertBuilder); ok && ib.s != nil {
+		return ib.s
+	}
+	return q
+}
+
+func renderStatement(q any, rc *renderContext) bool {
+	defer rc.tags(q)
+	switch q := statement(q).(type) {
+	case queryHelper:
+		render(q, rc)
+	case selfRenderer:
+		if rc.format != nil && rc.format.Lowercase {
+			// Definitions are always written in upper case.
+			f := *rc.format
+			f.Lowercase = false
+			rc.format = &f
+		}
+		q.render(rc)
+	default:
+		return false
+	}
+	return true
+}
+
+func validateStatement(q any, d *Dialect) error {
+	switch q := statement(q).(type) {
+	case queryHelper:
+		return validate(q, d)
+	case selfRenderer:
+		return q.validate(d)
+	}
+	return ErrUnsupportedStatement
+}
+
+// SQL renders the statement without validating it. Use Build to render a
+// statement only when it is valid.
+func SQL[T any](q T) string {
+	rc := newRenderContext()
+	renderStatement(q, rc)
+	return rc.sb.String()
+}
+
+// Build runs the hooks given with WithHooks, validates the statement and
+// renders it. The returned error joins every *BuildError found in the
+// statement. With CaptureArgs, it fails with ErrMissingValue when a
+// placeholder without a value comes before a bound value.
+func Build[T any](q T, opts ...RenderOption) (string, error) {
+	rc := newRenderContext(opts...)
+	var holes []int
+	trim := rc.capture != nil && rc.holes == nil
+	if trim {
+		rc.holes = &holes
+	}
+	s, err := rc.hook(q)
+	if err != nil {
+		return "", err
+	}
+	if err := validateStatement(s, rc.dialect); err != nil {
+		return "", err
+	}
+	renderStatement(s, rc)
+	if rc.err != nil {
+		return "", rc.err
+	}
+	if rc.capture != nil {
+		args := rc.args
+		if trim {
+			var left []int
+			if args, left = trimHoles(args, holes); len(left) != 0 {
+				return "", fmt.Errorf("%w: placeholder %d has no value", ErrMissingValue, left[0]+1)
 			}
+		}
+		*rc.capture = append(args, rc.extra...)
+	}
+	return rc.sb.String(), nil
+}
 
-			WhereSQL(q, &sb)
-			OrderBySQL(q, &sb)
+// CaptureArgs stores the values bound to the statement by Build in args, in
+// placeholder order. Unlike Args, they include the values bound by hooks and
+// by WithArgs. The values of the placeholders left without one after the
+// last bound value have to be supplied by the caller after them.
+func CaptureArgs(args *[]any) RenderOption {
+	return func(rc *renderContext) {
+		rc.capture = args
+	}
+}
 
-		case InsertQuery:
-			sb.WriteString("INSERT")
-			sb.WriteString(q.GetTable())
+// WithArgs binds the values to the placeholders without a bound value, in
+// the order the placeholders are rendered. Values left over are appended to
+// the values stored by CaptureArgs.
+func WithArgs(args ...any) RenderOption {
+	return func(rc *renderContext) {
+		rc.extra = args
+	}
+}
+
+// prepare runs the hooks for a render which can't fail. A statement
+// rejected by a hook is rendered as it is.
+func (rc *renderContext) prepare(q any) any {
+	if s, err := rc.hook(q); err == nil {
+		return s
+	}
+	return q
+}
 
-			sb.WriteString(" (")
-			sb.WriteString(strings.TrimSpace(strings.Join(q.GetColumns(), ", ")))
-			sb.WriteString(")")
+// Err returns the errors found in the statement regardless of the dialect
+// it is rendered for.
+func Err[T any](q T) error {
+	return validateStatement(q, nil)
+}
 
-			sb.WriteString(" VALUES ")
-			sb.WriteString("(")
+// Args returns the values bound to the statement in placeholder order. A
+// placeholder without a bound value holds nil when a bound value follows it,
+// e.g. in Where("org_id", Equals).AndOptional("name", Equals, name), so the
+// values keep their positions. The values of the placeholders after the
+// last bound value have to be supplied by the caller after the returned
+// ones. Use WithArgs or Compile to bind every unbound placeholder in place.
+func Args[T any](q T) []any {
+	rc := newRenderContext()
+	var holes []int
+	rc.holes = &holes
+	renderStatement(q, rc)
+	args, _ := trimHoles(rc.args, holes)
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
 
-			cols := make([]string, len(q.GetColumns()))
-			for i := 0; i < len(q.GetColumns()); i++ {
-				cols = append(cols, fmt.Sprintf("$%d", *pos))
-				*pos++
+// trimHoles drops the placeholders without a value after the last bound
+// value from args and returns the holes left before it.
+func trimHoles(args []any, holes []int) ([]any, []int) {
+	for len(holes) > 0 && holes[len(holes)-1] == len(args)-1 {
+		args, holes = args[:len(args)-1], holes[:len(holes)-1]
+	}
+	return args, holes
+}
+
+func render(q queryHelper, rc *renderContext) {
+	sb := &rc.sb
+
+	switch any(q).(type) {
+	case InsertQuery:
+		rc.keyword("INSERT INTO")
+		sb.WriteString(" ")
+		sb.WriteString(q.GetTable())
+
+		sb.WriteString(" (")
+		if rc.list("", q.GetColumns()) {
+			rc.newline("", "")
+		}
+		sb.WriteString(")")
+
+		if ib, ok := q.(*InsertBuilder); ok && ib.s == nil {
+			rc.clause("VALUES")
+			rows := ib.values
+			if len(rows) == 0 || rc.normalize {
+				rows = [][]any{nil}
+			}
+			for r, row := range rows {
+				if r > 0 {
+					sb.WriteString(",")
+				}
+				if len(rows) > 1 {
+					rc.newline(rc.indent(), " ")
+				} else {
+					sb.WriteString(" ")
+				}
+				sb.WriteString("(")
+				for i, c := range q.GetColumns() {
+					if i > 0 {
+						sb.WriteString(", ")
+					}
+					if i < len(ib.preset) {
+						rc.bind(c, ib.presetValues, i)
+					} else {
+						rc.bind(c, row, i-len(ib.preset))
+					}
+				}
+				sb.WriteString(")")
 			}
-			sb.WriteString(strings.TrimSuffix(strings.Join(cols, ", "), ", "))
-			sb.WriteString(")")
+		}
 
-			ReturningSQL(q, &sb)
+		// The RETURNING of an INSERT ... SELECT follows the SELECT.
+		if ib, ok := q.(*InsertBuilder); !ok || ib.s == nil {
+			rc.returning(q)
+		}
+
+	case SelectQuery:
+		p, insert := q.GetParent().(queryHelper)
+		if insert {
+			render(p, rc)
+			rc.newline("", " ")
+		}
 
-		case UpdateQuery:
-			sb.WriteString("UPDATE")
+		rc.keyword("SELECT")
+		if len(q.GetColumns()) == 0 {
+			sb.WriteString(" *")
+		} else {
+			rc.list(" ", q.GetColumns())
+		}
+		rc.clause("FROM")
+		sb.WriteString(" ")
+		sb.WriteString(q.GetTable())
+
+		if alias := q.GetAlias(); alias != "" {
+			sb.WriteString(" ")
+			rc.keyword("AS")
 			sb.WriteString(" ")
-			sb.WriteString(q.GetTable())
-			sb.WriteString(" SET ")
+			sb.WriteString(alias)
+		}
 
-			cols := make([]string, len(q.GetColumns()))
-			for i, c := range q.GetColumns() {
-				cols[i] = fmt.Sprintf("%s = $%d", c, *pos)
-				*pos++
+		// Pad the joins so their ON conditions line up.
+		width := 0
+		if rc.format != nil {
+			for _, join := range q.GetJoins() {
+				width = max(width, join.width())
 			}
-			sb.WriteString(strings.TrimSuffix(strings.Join(cols, ", "), ", "))
-			WhereSQL(q, &sb)
-			ReturningSQL(q, &sb)
+		}
+		for _, join := range q.GetJoins() {
+			join.render(rc, width)
+		}
+
+		rc.where(q)
+		rc.orderBy(q)
+		if insert {
+			rc.returning(p)
+		}
 
-		case DeleteQuery:
-			sb.WriteString("DELETE")
-			sb.WriteString(" FROM ")
-			sb.WriteString(q.GetTable())
-			WhereSQL(q, &sb)
+	case UpdateQuery:
+		rc.keyword("UPDATE")
+		sb.WriteString(" ")
+		sb.WriteString(q.GetTable())
+		rc.clause("SET")
+
+		set := make([]string, len(q.GetColumns()))
+		for i, c := range q.GetColumns() {
+			set[i] = rc.fragment(func(rc *renderContext) {
+				rc.sb.WriteString(c)
+				rc.sb.WriteString(" = ")
+				ub, ok := q.(*UpdateBuilder)
+				switch {
+				case !ok:
+					rc.placeholder()
+				case ub.exprs[c] != "":
+					rc.sb.WriteString(ub.exprs[c])
+				default:
+					rc.bind(c, ub.values, i)
+				}
+			})
 		}
+		rc.list(" ", set)
+		rc.where(q)
+		rc.returning(q)
 
-		return sb.String()
+	case DeleteQuery:
+		rc.keyword("DELETE FROM")
+		sb.WriteString(" ")
+		sb.WriteString(q.GetTable())
+		rc.where(q)
 	}
-	return ""
 }
@@ -72,6 +72,15 @@ func TestQuery(t *testing.T) {
 		require.Equal(t, "INSERT INTO users (user_id, name) SELECT id, name FROM users", s)
 	})
 
+	t.Run("case=insert into with select rendered from the insert", func(t *testing.T) {
+		q := Insert("user_id", "name").Into("users").Returning("user_id")
+		q.Select("id", "name").From("accounts").Where("id", LessThan)
+		require.Equal(t, "INSERT INTO users (user_id, name) SELECT id, name FROM accounts WHERE id < $1 RETURNING user_id", q.SQL())
+
+		_, err := q.Build(WithDialect(MySQL))
+		require.ErrorIs(t, err, ErrReturningUnsupported)
+	})
+
 	t.Run("case=select where in", func(t *testing.T) {
 		s := Select("id", "username").From("users").Where("id", In(3)).SQL()
 		require.Equal(t, "SELECT id, username FROM users WHERE id IN ($1, $2, $3)", s)
@@ -116,4 +125,22 @@ func TestQuery(t *testing.T) {
 		s := Delete().From("users").Where("id", NotEqual).And("name", In(3)).SQL()
 		require.Equal(t, "DELETE FROM users WHERE id != $1 AND name IN ($2, $3, $4)", s)
 	})
+
+	t.Run("case=select then by", func(t *testing.T) {
+		q := Select().From("users").OrderBy(Desc, "created_at").ThenBy(Asc, "last_name", "first_name")
+		require.Equal(t, "SELECT * FROM users ORDER BY created_at DESC, last_name, first_name ASC", q.SQL())
+		require.Equal(t, "SELECT * FROM users ORDER BY id ASC", Select().From("users").ThenBy(Asc, "id").SQL())
+		require.Equal(t, q.SQL(), q.Clone().SQL())
+	})
+
+	t.Run("case=select filter", func(t *testing.T) {
+		q := Select().From("users").Where("org_id", Equals).Or("public", IsTrue).Parent().
+			Filter(Cond("active", IsTrue), AnyOf(Cond("name", Like, "a%"), AllOf(Cond("age", GreaterThan, 18), Cond("role", In(2), "a", "b"))))
+		require.Equal(t, "SELECT * FROM users WHERE active IS TRUE AND (name LIKE $1 OR (age > $2 AND role IN ($3, $4))) "+
+			"AND (org_id = $5 OR public IS TRUE)", q.SQL())
+		require.Equal(t, []any{"a%", 18, "a", "b"}, q.Args())
+
+		q = Select().From("users").Filter(AllOf(Cond("a", Equals, 1), Cond("b", Equals, 2)), AllOf())
+		require.Equal(t, "SELECT * FROM users WHERE a = $1 AND b = $2", q.SQL())
+	})
 }
@@ -0,0 +1,153 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Benehiko/sqlbuilder/internal/fields"
+)
+
+// structRows returns the struct values held by v, which may be a struct, a
+// pointer to a struct or a slice of either.
+func structRows(v any) (reflect.Type, []reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return rv.Type(), []reflect.Value{rv}, nil
+	case reflect.Slice, reflect.Array:
+		t := indirect(rv.Type().Elem())
+		if t.Kind() != reflect.Struct {
+			break
+		}
+		rows := make([]reflect.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			e := rv.Index(i)
+			for e.Kind() == reflect.Pointer {
+				e = e.Elem()
+			}
+			if !e.IsValid() {
+				return nil, nil, fmt.Errorf("%w: nil element at index %d", ErrInvalidStruct, i)
+			}
+			rows = append(rows, e)
+		}
+		return t, rows, nil
+	}
+	return nil, nil, fmt.Errorf("%w: got %T", ErrInvalidStruct, v)
+}
+
+// InsertStruct inserts v into table, taking the columns and values from the
+// `db` tags of its fields. v may be a struct, a pointer to a struct or a
+// slice of either to insert multiple rows. An empty slice is rejected.
+//
+// Fields tagged readonly are never inserted. Fields tagged omitempty are left
+// out when they hold their zero value in every row.
+func InsertStruct(table string, v any) InsertIntoQuery {
+	ib := &InsertBuilder{table: table}
+
+	t, rows, err := structRows(v)
+	if err == nil && len(rows) == 0 {
+		err = fmt.Errorf("%w: no rows in %T", ErrInvalidStruct, v)
+	}
+	if err != nil {
+		ib.err = err
+		return ib
+	}
+
+	var columns []fields.Field
+	for _, f := range fields.Of(t) {
+		if f.ReadOnly {
+			continue
+		}
+		if f.OmitEmpty && allZero(f, rows) {
+			continue
+		}
+		columns = append(columns, f)
+	}
+
+	for _, f := range columns {
+		ib.columns = append(ib.columns, f.Column)
+	}
+	for _, row := range rows {
+		values := make([]any, len(columns))
+		for i, f := range columns {
+			values[i], _ = f.Value(row)
+		}
+		ib.values = append(ib.values, values)
+	}
+	return ib
+}
+
+func allZero(f fields.Field, rows []reflect.Value) bool {
+	for _, row := range rows {
+		if _, zero := f.Value(row); !zero {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateStruct updates table with the values of the struct v, taking the
+// columns from the `db` tags of its fields. v may be a struct or a pointer
+// to one.
+//
+// Fields tagged pk are matched in the WHERE clause instead of being set, a
+// struct without them is rejected. Conditions added with Where are ANDed
+// with the primary key. Fields tagged readonly are never set and fields
+// tagged omitempty are not set when they hold their zero value.
+func UpdateStruct(table string, v any) UpdateWhereQuery {
+	b := &UpdateBuilder{table: table}
+
+	t, rows, err := structRows(v)
+	if err == nil && indirect(reflect.TypeOf(v)).Kind() != reflect.Struct {
+		err = fmt.Errorf("%w: UpdateStruct expects a single struct", ErrInvalidStruct)
+	}
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	row := rows[0]
+	var key []*WhereCondition
+	for _, f := range fields.Of(t) {
+		value, zero := f.Value(row)
+		switch {
+		case f.PK:
+			key = append(key, Cond(f.Column, Equals, value))
+		case f.ReadOnly, f.OmitEmpty && zero:
+		default:
+			b.columns = append(b.columns, f.Column)
+			b.values = append(b.values, value)
+		}
+	}
+	if key == nil {
+		b.err = fmt.Errorf("%w: %s has no field tagged pk", ErrInvalidStruct, t)
+	}
+	b.key = AllOf(key...)
+	return b
+}
+
+// indirect returns the type t points to, through any number of pointers.
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// SelectFor selects the columns mapped by the `db` tags of T. The columns are
+// prefixed with the alias of the table when it is given with As.
+func SelectFor[T any]() FromQuery[SelectFromQuery] {
+	s := &SelectBuilder{qualify: true}
+
+	t := indirect(reflect.TypeFor[T]())
+	if t.Kind() != reflect.Struct {
+		s.err = fmt.Errorf("%w: got %s", ErrInvalidStruct, t)
+		return s
+	}
+	s.columns = fields.Columns(t)
+	return s
+}
@@ -0,0 +1,135 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Benehiko/sqlbuilder/internal/fields"
+)
+
+type (
+	testTimestamps struct {
+		CreatedAt time.Time `db:"created_at,readonly"`
+	}
+	testUser struct {
+		ID       int    `db:"id,pk,omitempty"`
+		Username string `db:"username"`
+		Email    string `db:"email,omitempty"`
+		Ignored  string `db:"-"`
+		internal string
+		testTimestamps
+	}
+)
+
+func TestInsertStruct(t *testing.T) {
+	t.Run("case=single struct", func(t *testing.T) {
+		q := InsertStruct("users", &testUser{Username: "foo", Ignored: "bar"})
+
+		s, err := q.Build()
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO users (username) VALUES ($1)", s)
+		require.Equal(t, []any{"foo"}, q.Args())
+	})
+
+	t.Run("case=slice of structs", func(t *testing.T) {
+		q := InsertStruct("users", []testUser{
+			{ID: 1, Username: "foo"},
+			{ID: 2, Username: "bar", Email: "bar@example.com"},
+		}).Returning("id")
+
+		s, err := q.Build()
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO users (id, username, email) VALUES ($1, $2, $3), ($4, $5, $6) RETURNING id", s)
+		require.Equal(t, []any{1, "foo", "", 2, "bar", "bar@example.com"}, q.Args())
+	})
+
+	t.Run("case=not a struct", func(t *testing.T) {
+		_, err := InsertStruct("users", 1).Build()
+		require.ErrorIs(t, err, ErrInvalidStruct)
+	})
+
+	t.Run("case=empty slice", func(t *testing.T) {
+		_, err := InsertStruct("users", []testUser{}).Build()
+		require.ErrorIs(t, err, ErrInvalidStruct)
+	})
+
+	t.Run("case=multiple rows with Values", func(t *testing.T) {
+		q := Insert("id", "name").Into("users").Values(1, "foo").Values(2, "bar")
+		require.Equal(t, "INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4)", q.SQL())
+		require.Equal(t, []any{1, "foo", 2, "bar"}, q.Args())
+	})
+}
+
+func TestUpdateStruct(t *testing.T) {
+	t.Run("case=primary key in where", func(t *testing.T) {
+		q := UpdateStruct("users", testUser{ID: 7, Username: "foo", Email: "foo@example.com"})
+
+		s, err := q.Build()
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET username = $1, email = $2 WHERE id = $3", s)
+		require.Equal(t, []any{"foo", "foo@example.com", 7}, q.Args())
+	})
+
+	t.Run("case=returning", func(t *testing.T) {
+		q := UpdateStruct("users", testUser{ID: 7, Username: "foo"}).Returning("created_at")
+		require.Equal(t, "UPDATE users SET username = $1 WHERE id = $2 RETURNING created_at", q.SQL())
+	})
+
+	t.Run("case=slice is rejected", func(t *testing.T) {
+		require.ErrorIs(t, UpdateStruct("users", []testUser{{}}).Err(), ErrInvalidStruct)
+	})
+
+	t.Run("case=pointer to pointer", func(t *testing.T) {
+		u := &testUser{ID: 7, Username: "foo"}
+		q := UpdateStruct("users", &u)
+		require.NoError(t, q.Err())
+		require.Equal(t, "UPDATE users SET username = $1 WHERE id = $2", q.SQL())
+	})
+
+	t.Run("case=struct without primary key is rejected", func(t *testing.T) {
+		type user struct {
+			Name string `db:"name"`
+		}
+		require.ErrorIs(t, UpdateStruct("users", user{Name: "foo"}).Err(), ErrInvalidStruct)
+	})
+
+	t.Run("case=where is ANDed with the primary key", func(t *testing.T) {
+		q := UpdateStruct("users", testUser{ID: 7, Username: "foo"})
+		q.Where("org_id", Equals).Or("owner_id", Equals)
+		require.Equal(t, "UPDATE users SET username = $1 WHERE id = $2 AND (org_id = $3 OR owner_id = $4)", q.SQL())
+		require.Equal(t, []any{"foo", 7}, q.Args())
+
+		hooks := &Hooks{}
+		hooks.Register(func(s *Structure) error {
+			s.Filter("tenant_id", Equals, 1)
+			return nil
+		})
+		s, err := q.Build(WithHooks(hooks))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE users SET username = $1 WHERE tenant_id = $2 AND id = $3 AND (org_id = $4 OR owner_id = $5)", s)
+	})
+}
+
+func TestStructColumns(t *testing.T) {
+	require.Equal(t, []string{"id", "username", "email", "created_at"}, fields.Columns(reflect.TypeOf(testUser{})))
+}
+
+func TestSelectFor(t *testing.T) {
+	t.Run("case=columns from tags", func(t *testing.T) {
+		s, err := SelectFor[testUser]().From("users").Where("id", Equals).Build()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT id, username, email, created_at FROM users WHERE id = $1", s)
+	})
+
+	t.Run("case=alias prefixed", func(t *testing.T) {
+		s := SelectFor[*testUser]().From("users").As("u").InnerJoin("roles").As("r").On("r.id", "u.role_id").SQL()
+		require.Equal(t, "SELECT u.id, u.username, u.email, u.created_at FROM users AS u INNER JOIN roles AS r ON r.id = u.role_id", s)
+	})
+
+	t.Run("case=not a struct", func(t *testing.T) {
+		require.ErrorIs(t, SelectFor[string]().From("users").Err(), ErrInvalidStruct)
+	})
+}
@@ -0,0 +1,332 @@
+package sqlbuilder
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type (
+	CreateTableBuilder struct {
+		table       string
+		ifNotExists bool
+		columns     []*ColumnDef
+		constraints []*Constraint
+		tags        Tags
+	}
+	DropTableBuilder struct {
+		tables   []string
+		ifExists bool
+		cascade  bool
+		tags     Tags
+	}
+)
+
+var (
+	_ Statement    = (*CreateTableBuilder)(nil)
+	_ selfRenderer = (*CreateTableBuilder)(nil)
+	_ Statement    = (*DropTableBuilder)(nil)
+	_ selfRenderer = (*DropTableBuilder)(nil)
+)
+
+// CreateTable creates the table with the given name.
+//
+//	CreateTable("users").
+//		Columns(
+//			Column("id", BigInt).Identity().PrimaryKey(),
+//			Column("email", Varchar(255)).NotNull().Unique(),
+//			Column("created_at", Timestamp).NotNull().Default("now()"),
+//		)
+func CreateTable(table string) *CreateTableBuilder {
+	return &CreateTableBuilder{table: table}
+}
+
+func (b *CreateTableBuilder) IfNotExists() *CreateTableBuilder {
+	b.ifNotExists = true
+	return b
+}
+
+func (b *CreateTableBuilder) Columns(columns ...*ColumnDef) *CreateTableBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Constraints adds table constraints, see PrimaryKey, Unique, ForeignKey
+// and Check.
+func (b *CreateTableBuilder) Constraints(constraints ...*Constraint) *CreateTableBuilder {
+	b.constraints = append(b.constraints, constraints...)
+	return b
+}
+
+// When calls f with the builder when cond is true.
+func (b *CreateTableBuilder) When(cond bool, f func(b *CreateTableBuilder)) *CreateTableBuilder {
+	if cond {
+		f(b)
+	}
+	return b
+}
+
+func (b *CreateTableBuilder) Clone() *CreateTableBuilder {
+	c := &CreateTableBuilder{
+		table:       b.table,
+		ifNotExists: b.ifNotExists,
+		tags:        maps.Clone(b.tags),
+	}
+	for _, col := range b.columns {
+		c.columns = append(c.columns, col.clone())
+	}
+	for _, con := range b.constraints {
+		c.constraints = append(c.constraints, con.clone())
+	}
+	return c
+}
+
+func (b *CreateTableBuilder) SQL() string {
+	return SQL(b)
+}
+
+func (b *CreateTableBuilder) Pretty() string {
+	return Pretty(b)
+}
+
+func (b *CreateTableBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(b, opts...)
+}
+
+func (b *CreateTableBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(b, opts...)
+}
+
+func (b *CreateTableBuilder) Tag(key, value string) Statement {
+	b.tags.set(key, value)
+	return b
+}
+
+func (b *CreateTableBuilder) GetTags() Tags {
+	return b.tags
+}
+
+func (b *CreateTableBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(b, opts...)
+}
+
+func (b *CreateTableBuilder) Err() error {
+	return Err(b)
+}
+
+func (b *CreateTableBuilder) Args() []any {
+	return nil
+}
+
+func (b *CreateTableBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(b, opts...)
+}
+
+func (b *CreateTableBuilder) render(rc *renderContext) {
+	sb := &rc.sb
+	sb.WriteString("CREATE TABLE ")
+	if b.ifNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	sb.WriteString(b.table)
+	sb.WriteString(" (")
+	n := 0
+	next := func() {
+		if n > 0 {
+			sb.WriteString(",")
+			rc.newline(rc.indent(), " ")
+		} else {
+			rc.newline(rc.indent(), "")
+		}
+		n++
+	}
+	for _, c := range b.columns {
+		next()
+		c.render(rc)
+	}
+	for _, c := range b.constraints {
+		next()
+		c.render(sb)
+	}
+	if rc.dialect == MySQL {
+		for _, c := range b.columns {
+			if c.references != nil {
+				next()
+				c.references.render(sb)
+			}
+		}
+	}
+	rc.newline("", "")
+	sb.WriteString(")")
+}
+
+func (b *CreateTableBuilder) validate(d *Dialect) error {
+	var errs []error
+	add := func(column string, err error) {
+		errs = append(errs, &BuildError{
+			Statement: "CREATE TABLE",
+			Table:     b.table,
+			Column:    column,
+			Err:       err,
+		})
+	}
+
+	if b.table == "" {
+		add("", ErrMissingTable)
+	}
+	if len(b.columns) == 0 {
+		add("", ErrNoColumns)
+	}
+
+	primaryKeys := 0
+	for _, c := range b.columns {
+		if c.typ.name == "" {
+			add(c.name, ErrMissingType)
+		}
+		if c.primaryKey {
+			primaryKeys++
+		}
+		if c.references != nil {
+			if err := c.references.validate(); err != nil {
+				add(c.name, err)
+			}
+		}
+	}
+	for _, c := range b.constraints {
+		if c.kind == primaryKeyConstraint {
+			primaryKeys++
+		}
+		if err := c.validate(); err != nil {
+			add("", err)
+		}
+	}
+	if primaryKeys > 1 {
+		add("", ErrMultiplePrimaryKeys)
+	}
+
+	if d != nil {
+		if b.ifNotExists && !d.ifNotExists {
+			add("", fmt.Errorf("%w: IF NOT EXISTS", d.unsupported()))
+		}
+		if d == SQLite {
+			for _, c := range b.columns {
+				if c.identity && (!c.primaryKey || primaryKeys > 1) {
+					add(c.name, fmt.Errorf("%w: identity column which is not the primary key", d.unsupported()))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Constraint) validate() error {
+	switch {
+	case c.kind != checkConstraint && len(c.columns) == 0:
+		return fmt.Errorf("%w: %s constraint", ErrNoColumns, c.kind)
+	case c.kind == checkConstraint && c.check == "":
+		return fmt.Errorf("%w: empty CHECK constraint", ErrInvalidConstraint)
+	case c.kind == foreignKeyConstraint && c.refTable == "":
+		return fmt.Errorf("%w: foreign key without referenced table", ErrInvalidConstraint)
+	case c.kind == foreignKeyConstraint && len(c.refColumns) != 0 && len(c.refColumns) != len(c.columns):
+		return fmt.Errorf("%w: foreign key references %d columns from %d columns", ErrInvalidConstraint, len(c.refColumns), len(c.columns))
+	}
+	return nil
+}
+
+// DropTable drops the given tables.
+func DropTable(tables ...string) *DropTableBuilder {
+	return &DropTableBuilder{tables: tables}
+}
+
+func (b *DropTableBuilder) IfExists() *DropTableBuilder {
+	b.ifExists = true
+	return b
+}
+
+// Cascade also drops the objects depending on the tables.
+func (b *DropTableBuilder) Cascade() *DropTableBuilder {
+	b.cascade = true
+	return b
+}
+
+// When calls f with the builder when cond is true.
+func (b *DropTableBuilder) When(cond bool, f func(b *DropTableBuilder)) *DropTableBuilder {
+	if cond {
+		f(b)
+	}
+	return b
+}
+
+func (b *DropTableBuilder) Clone() *DropTableBuilder {
+	c := *b
+	c.tables = slices.Clone(b.tables)
+	c.tags = maps.Clone(b.tags)
+	return &c
+}
+
+func (b *DropTableBuilder) SQL() string {
+	return SQL(b)
+}
+
+func (b *DropTableBuilder) Pretty() string {
+	return Pretty(b)
+}
+
+func (b *DropTableBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(b, opts...)
+}
+
+func (b *DropTableBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(b, opts...)
+}
+
+func (b *DropTableBuilder) Tag(key, value string) Statement {
+	b.tags.set(key, value)
+	return b
+}
+
+func (b *DropTableBuilder) GetTags() Tags {
+	return b.tags
+}
+
+func (b *DropTableBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(b, opts...)
+}
+
+func (b *DropTableBuilder) Err() error {
+	return Err(b)
+}
+
+func (b *DropTableBuilder) Args() []any {
+	return nil
+}
+
+func (b *DropTableBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(b, opts...)
+}
+
+func (b *DropTableBuilder) render(rc *renderContext) {
+	sb := &rc.sb
+	sb.WriteString("DROP TABLE ")
+	if b.ifExists {
+		sb.WriteString("IF EXISTS ")
+	}
+	sb.WriteString(strings.Join(b.tables, ", "))
+	if b.cascade {
+		sb.WriteString(" CASCADE")
+	}
+}
+
+func (b *DropTableBuilder) validate(d *Dialect) error {
+	var errs []error
+	if len(b.tables) == 0 || slices.Contains(b.tables, "") {
+		errs = append(errs, &BuildError{Statement: "DROP TABLE", Err: ErrMissingTable})
+	}
+	if d != nil && b.cascade && !d.cascade {
+		errs = append(errs, &BuildError{Statement: "DROP TABLE", Err: fmt.Errorf("%w: CASCADE", d.unsupported())})
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package sqlbuilder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTable(t *testing.T) {
+	users := func() *CreateTableBuilder {
+		return CreateTable("users").IfNotExists().
+			Columns(
+				Column("id", BigInt).Identity().PrimaryKey(),
+				Column("tenant_id", UUID).NotNull(),
+				Column("email", Varchar(255)).NotNull().Unique(),
+				Column("score", Decimal(10, 2)).Default("0").Check("score >= 0"),
+				Column("settings", JSON),
+				Column("role_id", Integer).References("roles", "id", Cascade),
+				Column("created_at", Timestamp).NotNull().Default("CURRENT_TIMESTAMP"),
+			).
+			Constraints(
+				Unique("tenant_id", "email").Named("users_tenant_email"),
+				ForeignKey("tenant_id").References("tenants", "id").OnDelete(Restrict).OnUpdate(Cascade),
+			)
+	}
+
+	t.Run("case=postgres", func(t *testing.T) {
+		s, err := users().Build()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE TABLE IF NOT EXISTS users ("+
+			"id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+			"tenant_id UUID NOT NULL, "+
+			"email VARCHAR(255) NOT NULL UNIQUE, "+
+			"score NUMERIC(10, 2) DEFAULT 0 CHECK (score >= 0), "+
+			"settings JSONB, "+
+			"role_id INTEGER REFERENCES roles (id) ON DELETE CASCADE, "+
+			"created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, "+
+			"CONSTRAINT users_tenant_email UNIQUE (tenant_id, email), "+
+			"FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE RESTRICT ON UPDATE CASCADE)", s)
+	})
+
+	t.Run("case=mysql", func(t *testing.T) {
+		s, err := users().Build(WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Contains(t, s, "id BIGINT AUTO_INCREMENT PRIMARY KEY, tenant_id CHAR(36) NOT NULL")
+		require.Contains(t, s, "created_at DATETIME(6) NOT NULL")
+		require.Contains(t, s, "role_id INT, ")
+		require.True(t, strings.HasSuffix(s, ", FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE RESTRICT ON UPDATE CASCADE, "+
+			"FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE)"), s)
+	})
+
+	t.Run("case=sqlite", func(t *testing.T) {
+		s, err := users().Build(WithDialect(SQLite))
+		require.NoError(t, err)
+		require.Contains(t, s, "(id INTEGER PRIMARY KEY AUTOINCREMENT, tenant_id TEXT NOT NULL")
+	})
+
+	t.Run("case=sqlserver", func(t *testing.T) {
+		_, err := users().Build(WithDialect(SQLServer))
+		require.ErrorIs(t, err, ErrUnsupportedByDialect)
+
+		s, err := CreateTable("users").
+			Columns(Column("id", BigInt).Identity().PrimaryKey(), Column("name", Text)).
+			Build(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, "CREATE TABLE users (id BIGINT IDENTITY(1,1) PRIMARY KEY, name NVARCHAR(MAX))", s)
+	})
+
+	t.Run("case=table primary key", func(t *testing.T) {
+		s := CreateTable("memberships").
+			Columns(Column("user_id", BigInt).NotNull(), Column("group_id", BigInt).NotNull(), Column("role", RawType("member_role"))).
+			Constraints(PrimaryKey("user_id", "group_id"), Check("role <> 'owner'").Named("no_owner")).
+			SQL()
+		require.Equal(t, "CREATE TABLE memberships (user_id BIGINT NOT NULL, group_id BIGINT NOT NULL, role member_role, "+
+			"PRIMARY KEY (user_id, group_id), CONSTRAINT no_owner CHECK (role <> 'owner'))", s)
+	})
+
+	t.Run("case=invalid", func(t *testing.T) {
+		require.ErrorIs(t, CreateTable("").Columns(Column("id", BigInt)).Err(), ErrMissingTable)
+		require.ErrorIs(t, CreateTable("users").Err(), ErrNoColumns)
+		require.ErrorIs(t, CreateTable("users").Columns(Column("id", ColumnType{})).Err(), ErrMissingType)
+		require.ErrorIs(t, CreateTable("users").Columns(Column("id", BigInt).PrimaryKey()).Constraints(PrimaryKey("id")).Err(), ErrMultiplePrimaryKeys)
+		require.ErrorIs(t, CreateTable("users").Columns(Column("id", BigInt)).Constraints(ForeignKey("id")).Err(), ErrInvalidConstraint)
+	})
+}
+
+func TestDropTable(t *testing.T) {
+	s, err := DropTable("users", "roles").IfExists().Cascade().Build()
+	require.NoError(t, err)
+	require.Equal(t, "DROP TABLE IF EXISTS users, roles CASCADE", s)
+
+	_, err = DropTable("users").Cascade().Build(WithDialect(MySQL))
+	require.ErrorIs(t, err, ErrUnsupportedByDialect)
+
+	require.ErrorIs(t, DropTable().Err(), ErrMissingTable)
+}
@@ -0,0 +1,90 @@
+package sqlbuilder
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Tags are key/value pairs rendered after a statement as a comment in the
+// sqlcommenter format, e.g. /*route='%2Fusers',service='api'*/, to trace
+// statements back to the request which ran them.
+type Tags map[string]string
+
+// tagged is implemented by the builders, which carry their own tags.
+type tagged interface {
+	GetTags() Tags
+}
+
+type tagsKey struct{}
+
+// ContextWithTags returns a context carrying the tags together with those
+// already carried by ctx. Render with WithContext to add them to a
+// statement.
+func ContextWithTags(ctx context.Context, tags Tags) context.Context {
+	merged := maps.Clone(TagsFromContext(ctx))
+	if merged == nil {
+		merged = Tags{}
+	}
+	maps.Copy(merged, tags)
+	return context.WithValue(ctx, tagsKey{}, merged)
+}
+
+// TagsFromContext returns the tags carried by ctx.
+func TagsFromContext(ctx context.Context) Tags {
+	tags, _ := ctx.Value(tagsKey{}).(Tags)
+	return tags
+}
+
+// WithContext adds the tags carried by ctx to the statement and passes ctx
+// to the hooks. Tags of the statement take precedence over those of the
+// context.
+func WithContext(ctx context.Context) RenderOption {
+	return func(rc *renderContext) {
+		rc.ctx = ctx
+	}
+}
+
+// OmitTags renders the statement without its tags, e.g. to keep request
+// specific tags out of a Fingerprint.
+func OmitTags() RenderOption {
+	return func(rc *renderContext) {
+		rc.omitTags = true
+	}
+}
+
+func (t *Tags) set(key, value string) {
+	if *t == nil {
+		*t = Tags{}
+	}
+	(*t)[key] = value
+}
+
+// tags writes the tags of q and of the context as a trailing comment, with
+// the keys sorted and both keys and values URL encoded as sqlcommenter
+// requires.
+func (rc *renderContext) tags(q any) {
+	if rc.omitTags || rc.ctx == nil {
+		return
+	}
+	tags := maps.Clone(TagsFromContext(rc.ctx))
+	if t, ok := q.(tagged); ok {
+		if tags == nil {
+			tags = Tags{}
+		}
+		maps.Copy(tags, t.GetTags())
+	}
+	if len(tags) == 0 {
+		return
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		pairs = append(pairs, url.PathEscape(k)+"='"+url.PathEscape(tags[k])+"'")
+	}
+	rc.sb.WriteString(" /*")
+	rc.sb.WriteString(strings.Join(pairs, ","))
+	rc.sb.WriteString("*/")
+}
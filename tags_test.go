@@ -0,0 +1,48 @@
+package sqlbuilder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	t.Run("case=escaping", func(t *testing.T) {
+		q := Select("id").From("users").Where("id", Equals).
+			Tag("route", "/param*/d").Tag("db driver", "it's").Tag("controller", "index")
+		require.Equal(t, `SELECT id FROM users WHERE id = $1 /*controller='index',db%20driver='it%27s',route='%2Fparam%2A%2Fd'*/`, q.SQL())
+	})
+
+	t.Run("case=context", func(t *testing.T) {
+		ctx := ContextWithTags(context.Background(), Tags{"service": "api", "route": "/"})
+		ctx = ContextWithTags(ctx, Tags{"request_id": "1"})
+		require.Equal(t, Tags{"service": "api", "route": "/", "request_id": "1"}, TagsFromContext(ctx))
+
+		q := Update("users").Set("name").Tag("route", "/users")
+		s, err := q.Build(WithContext(ctx), WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, `UPDATE users SET name = ? /*request_id='1',route='%2Fusers',service='api'*/`, s)
+
+		s, err = CreateTable("t").Columns(Column("id", BigInt)).Build(WithContext(ctx), OmitTags())
+		require.NoError(t, err)
+		require.Equal(t, "CREATE TABLE t (id BIGINT)", s)
+	})
+
+	t.Run("case=clone", func(t *testing.T) {
+		q := Insert("id").Into("users")
+		q.Tag("a", "1")
+		c := q.Clone()
+		c.Tag("b", "2")
+		require.Equal(t, "INSERT INTO users (id) VALUES ($1) /*a='1'*/", q.SQL())
+		require.Equal(t, "INSERT INTO users (id) VALUES ($1) /*a='1',b='2'*/", c.SQL())
+	})
+
+	t.Run("case=fingerprint", func(t *testing.T) {
+		a := Select("id").From("users").Tag("request_id", "1")
+		b := Select("id").From("users").Tag("request_id", "2")
+		require.NotEqual(t, a.Fingerprint(), b.Fingerprint())
+		require.Equal(t, a.Fingerprint(OmitTags()), b.Fingerprint(OmitTags()))
+		require.Equal(t, "SELECT id FROM users", a.Fingerprint(OmitTags()).Normalized)
+	})
+}
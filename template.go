@@ -0,0 +1,92 @@
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Template is a statement rendered once by Compile, for hot paths running
+// the same statement over and over. It is safe for concurrent use.
+type Template struct {
+	sql  string
+	args []any
+	// holes are the indexes in args of the placeholders without a value.
+	holes []int
+	// params are the names of the parameters, by their index in args.
+	params map[int]string
+}
+
+// Compile renders the statement once, with the dialect and tags of opts,
+// and returns it as a Template. Changes made to the statement later don't
+// apply to the template. It fails with ErrUnsupportedOption given WithHooks
+// or WithContext, since the template would replay the values bound for the
+// first context on every run; use a Param and Bind for such values.
+func Compile[T any](q T, opts ...RenderOption) (*Template, error) {
+	if rc := newRenderContext(opts...); rc.hooks != nil || rc.ctx != context.Background() {
+		return nil, fmt.Errorf("%w: WithHooks and WithContext can't be compiled", ErrUnsupportedOption)
+	}
+	t := &Template{}
+	opts = append(opts, CaptureArgs(&t.args), func(rc *renderContext) {
+		rc.holes = &t.holes
+	})
+	s, err := Build(q, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.sql = s
+	for i, arg := range t.args {
+		if named, ok := arg.(sql.NamedArg); ok {
+			arg = named.Value
+		}
+		if p, ok := arg.(Param); ok {
+			if t.params == nil {
+				t.params = map[int]string{}
+			}
+			t.params[i] = string(p)
+		}
+	}
+	return t, nil
+}
+
+// SQL returns the rendered statement.
+func (t *Template) SQL() string {
+	return t.sql
+}
+
+// Args returns the values bound to the statement in placeholder order, with
+// values bound to the placeholders without a value as WithArgs does. It
+// fails with ErrMissingValue unless values has one value per placeholder.
+// The parameters of the statement are left unbound, use Bind to set them.
+func (t *Template) Args(values ...any) ([]any, error) {
+	if len(values) != len(t.holes) {
+		return nil, fmt.Errorf("%w: %d values for %d placeholders", ErrMissingValue, len(values), len(t.holes))
+	}
+	args := make([]any, len(t.args))
+	copy(args, t.args)
+	for i, pos := range t.holes {
+		args[pos] = values[i]
+	}
+	return args, nil
+}
+
+// Bind is Args with the value of params for each Param of the statement. It
+// fails with ErrMissingParam when params has no value for one of them.
+func (t *Template) Bind(params map[string]any, values ...any) ([]any, error) {
+	args, err := t.Args(values...)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range t.params {
+		v, ok := params[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingParam, name)
+		}
+		if named, ok := args[i].(sql.NamedArg); ok {
+			named.Value = v
+			v = named
+		}
+		args[i] = v
+	}
+	return args, nil
+}
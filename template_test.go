@@ -0,0 +1,126 @@
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func benchmarkQuery() SelectFromQuery {
+	return Select("u.id", "u.name", "r.name").From("users").As("u").
+		LeftJoin("roles").As("r").On("r.id", "u.role_id").
+		Where("u.org_id", Equals).And("u.status", In(3)).And("u.created_at", GreaterThanOrEqual).Parent().
+		Filter(Cond("u.deleted_at", IsNull)).
+		OrderBy(Desc, "u.created_at")
+}
+
+func TestCompile(t *testing.T) {
+	t.Run("case=args", func(t *testing.T) {
+		q := Update("users").Set("name", "email").Where("id", Equals).And("org_id", Equals).Parent().(UpdateWhereQuery)
+
+		tpl, err := q.Compile(WithDialect(SQLServer))
+		require.NoError(t, err)
+		s, err := q.Build(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, s, tpl.SQL())
+		require.Equal(t, "UPDATE users SET name = @p1, email = @p2 WHERE id = @p3 AND org_id = @p4", tpl.SQL())
+
+		args, err := tpl.Args("foo", "foo@example.com", 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, []any{"foo", "foo@example.com", 1, 2}, args)
+
+		// Changes to the statement don't apply to the template.
+		q.Where("id", Equals)
+		args, err = tpl.Args("bar", "bar@example.com", 3, 4)
+		require.NoError(t, err)
+		require.Equal(t, []any{"bar", "bar@example.com", 3, 4}, args)
+
+		_, err = tpl.Args("foo")
+		require.ErrorIs(t, err, ErrMissingValue)
+		_, err = tpl.Args("bar", "bar@example.com", 3, 4, "extra")
+		require.ErrorIs(t, err, ErrMissingValue)
+	})
+
+	t.Run("case=hooks", func(t *testing.T) {
+		hooks := &Hooks{}
+		hooks.Register(TenantScope{"users": "tenant_id"}.Hook())
+		q := Select().From("users").Where("id", Equals)
+
+		// The tenant of the first context would be bound on every run.
+		_, err := q.Compile(WithHooks(hooks))
+		require.ErrorIs(t, err, ErrUnsupportedOption)
+		_, err = q.Compile(WithContext(ContextWithTenant(context.Background(), 7)))
+		require.ErrorIs(t, err, ErrUnsupportedOption)
+
+		tpl, err := Select().From("users").Filter(Cond("tenant_id", Equals, Param("tenant_id")), Cond("id", Equals)).Compile()
+		require.NoError(t, err)
+		args, err := tpl.Bind(map[string]any{"tenant_id": 7}, 1)
+		require.NoError(t, err)
+		require.Equal(t, []any{7, 1}, args)
+	})
+
+	t.Run("case=statements", func(t *testing.T) {
+		tpl, err := Select().From("users").Where("id", Equals).Compile()
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM users WHERE id = $1", tpl.SQL())
+
+		tpl, err = Select().From("posts").Filter(Cond("author_id", Equals, Param("user_id")), Cond("id", Equals)).Compile(WithDialect(SQLServer))
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM posts WHERE author_id = @user_id AND id = @p1", tpl.SQL())
+		args, err := tpl.Bind(map[string]any{"user_id": 1}, 5)
+		require.NoError(t, err)
+		require.Equal(t, []any{sql.Named("user_id", 1), 5}, args)
+		_, err = tpl.Bind(nil, 5)
+		require.ErrorIs(t, err, ErrMissingParam)
+
+		tpl, err = Insert("name", "created_by").Into("posts").Values(Param("name"), Param("user_id")).Values(nil, Param("user_id")).Compile(WithDialect(MySQL))
+		require.NoError(t, err)
+		require.Equal(t, "INSERT INTO posts (name, created_by) VALUES (?, ?), (?, ?)", tpl.SQL())
+		args, err = tpl.Bind(map[string]any{"name": "foo", "user_id": 1})
+		require.NoError(t, err)
+		require.Equal(t, []any{"foo", 1, nil, 1}, args)
+
+		tpl, err = CreateIndex("i").On("users", "email").Compile()
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX i ON users (email)", tpl.SQL())
+
+		_, err = Delete().From("").Compile()
+		require.ErrorIs(t, err, ErrMissingTable)
+	})
+}
+
+func BenchmarkSelect(b *testing.B) {
+	values := []any{1, "active", "invited", "pending", "2024-01-01"}
+
+	b.Run("rebuild", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			q := benchmarkQuery()
+			_ = q.SQL()
+			_ = append(q.Args(), values...)
+		}
+	})
+
+	b.Run("sql", func(b *testing.B) {
+		q := benchmarkQuery()
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = q.SQL()
+			_ = append(q.Args(), values...)
+		}
+	})
+
+	b.Run("compiled", func(b *testing.B) {
+		tpl, err := benchmarkQuery().Compile()
+		require.NoError(b, err)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = tpl.SQL()
+			if _, err := tpl.Args(values...); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package sqlbuilder
+
+import (
+	"context"
+	"fmt"
+)
+
+// TenantScope maps the tables owned by tenants to their tenant column, e.g.
+// TenantScope{"users": "tenant_id"}. Register its Hook to restrict every
+// statement on those tables to the tenant of the context.
+type TenantScope map[string]string
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a context carrying the tenant, which is bound
+// by the Hook of a TenantScope.
+func ContextWithTenant(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by ctx.
+func TenantFromContext(ctx context.Context) (any, bool) {
+	tenant := ctx.Value(tenantKey{})
+	return tenant, tenant != nil
+}
+
+// Hook returns a Hook adding column = tenant to the where chain of every
+// SELECT, UPDATE and DELETE of a scoped table and to the ON condition of
+// every joined scoped table, and setting the column in every row inserted
+// into a scoped table, see Structure.SetValue. A statement touching a scoped table fails with
+// ErrMissingTenant when the context carries no tenant.
+func (t TenantScope) Hook() Hook {
+	return func(s *Structure) error {
+		if p, ok := s.q.GetParent().(*InsertBuilder); ok {
+			if _, scoped := t[p.table]; scoped {
+				return fmt.Errorf("%w: INSERT ... SELECT into tenant scoped table %s", ErrUnsupportedStatement, p.table)
+			}
+		}
+
+		column, scoped := t[s.Table()]
+		var joins []*Join
+		for _, j := range s.Joins() {
+			if _, ok := t[j.GetTable()]; ok {
+				joins = append(joins, j)
+			}
+		}
+		if !scoped && len(joins) == 0 {
+			return nil
+		}
+
+		tenant, ok := TenantFromContext(s.Context)
+		if !ok {
+			return ErrMissingTenant
+		}
+
+		if s.Kind == InsertKind {
+			s.SetValue(column, tenant)
+			return nil
+		}
+		for _, j := range joins {
+			j.Filter(qualify(j.GetAlias(), j.GetTable(), t[j.GetTable()]), Equals, tenant)
+		}
+		if scoped {
+			s.Filter(column, Equals, tenant)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package sqlbuilder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantScope(t *testing.T) {
+	hooks := &Hooks{}
+	hooks.Register(TenantScope{"users": "tenant_id", "roles": "org_id"}.Hook())
+	ctx := ContextWithTenant(context.Background(), 42)
+	build := func(t *testing.T, q Statement, args ...any) (string, []any) {
+		var bound []any
+		s, err := q.Build(WithHooks(hooks), WithContext(ctx), WithArgs(args...), CaptureArgs(&bound))
+		require.NoError(t, err)
+		return s, bound
+	}
+
+	t.Run("case=select", func(t *testing.T) {
+		s, args := build(t, Select("u.id").From("users").As("u").
+			InnerJoin("roles").On("roles.id", "u.role_id").
+			LeftJoin("teams").As("t").On("t.id", "u.team_id").
+			Where("u.name", Equals).Or("u.email", Equals), "a", "b")
+		require.Equal(t, "SELECT u.id FROM users AS u INNER JOIN roles ON roles.id = u.role_id AND roles.org_id = $1 "+
+			"LEFT JOIN teams AS t ON t.id = u.team_id WHERE u.tenant_id = $2 AND (u.name = $3 OR u.email = $4)", s)
+		require.Equal(t, []any{42, 42, "a", "b"}, args)
+
+		s, _ = build(t, Select().From("teams").InnerJoin("users").As("u").On("u.team_id", "teams.id"))
+		require.Equal(t, "SELECT * FROM teams INNER JOIN users AS u ON u.team_id = teams.id AND u.tenant_id = $1", s)
+
+		s, _ = build(t, Select().From("teams"))
+		require.Equal(t, "SELECT * FROM teams", s)
+	})
+
+	t.Run("case=update and delete", func(t *testing.T) {
+		s, args := build(t, Update("users").Set("name").Where("id", Equals), "foo", 1)
+		require.Equal(t, "UPDATE users SET name = $1 WHERE tenant_id = $2 AND id = $3", s)
+		require.Equal(t, []any{"foo", 42, 1}, args)
+
+		s, args = build(t, Delete().From("users"))
+		require.Equal(t, "DELETE FROM users WHERE tenant_id = $1", s)
+		require.Equal(t, []any{42}, args)
+	})
+
+	t.Run("case=insert", func(t *testing.T) {
+		s, args := build(t, Insert("name").Into("users").Values("a").Values("b"))
+		require.Equal(t, "INSERT INTO users (tenant_id, name) VALUES ($1, $2), ($3, $4)", s)
+		require.Equal(t, []any{42, "a", 42, "b"}, args)
+
+		s, args = build(t, Insert("tenant_id", "name").Into("users").Values(7, "a"))
+		require.Equal(t, "INSERT INTO users (tenant_id, name) VALUES ($1, $2)", s)
+		require.Equal(t, []any{42, "a"}, args)
+
+		_, err := Insert("id").Into("users").Select("id").From("accounts").Build(WithHooks(hooks), WithContext(ctx))
+		require.ErrorIs(t, err, ErrUnsupportedStatement)
+
+		// The tenant can't be left to the caller's values.
+		_, err = Insert("id", "tenant_id").Into("users").Build(WithHooks(hooks), WithContext(ctx), WithArgs(1, 99))
+		require.ErrorIs(t, err, ErrUnsupportedStatement)
+		_, err = Insert("id", "tenant_id").Into("users").Values(1, 7).Values().Build(WithHooks(hooks), WithContext(ctx))
+		require.ErrorIs(t, err, ErrUnsupportedStatement)
+	})
+
+	t.Run("case=missing tenant", func(t *testing.T) {
+		for _, q := range []Statement{
+			Select().From("users"),
+			Select().From("teams").LeftJoin("roles").On("roles.id", "teams.role_id"),
+			Update("users").Set("name"),
+			Delete().From("users"),
+			Insert("name").Into("users"),
+		} {
+			_, err := q.Build(WithHooks(hooks))
+			require.ErrorIs(t, err, ErrMissingTenant, q.SQL())
+		}
+		_, err := Select().From("teams").Build(WithHooks(hooks))
+		require.NoError(t, err)
+	})
+}
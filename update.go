@@ -1,17 +1,27 @@
 package sqlbuilder
 
+import (
+	"maps"
+	"slices"
+)
+
 type (
 	UpdateSetQuery interface {
 		Set(columns ...string) UpdateWhereQuery
 	}
 	UpdateWhereQuery interface {
 		Where[UpdateReturningQuery]
-		SQL() string
+		UpdateReturningQuery
+		// When calls f with the update when cond is true.
+		When(cond bool, f func(q UpdateWhereQuery)) UpdateWhereQuery
+		// WithDeleted updates the rows soft deleted according to a
+		// SoftDeleteScope too.
+		WithDeleted() UpdateWhereQuery
+		Clone() UpdateWhereQuery
+		Statement
 	}
 	UpdateReturningQuery interface {
-		Returning(columns ...string) interface {
-			SQL() string
-		}
+		Returning(columns ...string) Statement
 	}
 	UpdateQuery interface {
 		Update(table string) UpdateSetQuery
@@ -20,7 +30,16 @@ type (
 		table     string
 		columns   []string
 		returning []string
-		pos       int
+		values    []any
+		// exprs maps columns set to an SQL expression instead of a bound
+		// value.
+		exprs map[string]string
+		// key matches the primary key of the struct given to UpdateStruct,
+		// ANDed with the where chain.
+		key         *WhereCondition
+		withDeleted bool
+		err         error
+		tags        Tags
 		*WhereBuilder[UpdateReturningQuery]
 	}
 )
@@ -50,30 +69,123 @@ func (b *UpdateBuilder) Where(column string, operator Operator) WhereOptions[Upd
 			Op:      operator,
 		},
 	}
+	return b.WhereBuilder
+}
+
+func (b *UpdateBuilder) WhereOptional(column string, operator Operator, value any) WhereOptions[UpdateReturningQuery] {
+	b.WhereBuilder = &WhereBuilder[UpdateReturningQuery]{
+		parent: b,
+		where:  optional(column, operator, value),
+	}
+	return b.WhereBuilder
+}
+
+func (b *UpdateBuilder) When(cond bool, f func(q UpdateWhereQuery)) UpdateWhereQuery {
+	if cond {
+		f(b)
+	}
+	return b
+}
+
+func (b *UpdateBuilder) WithDeleted() UpdateWhereQuery {
+	b.withDeleted = true
 	return b
 }
 
-func (b *UpdateBuilder) Returning(columns ...string) interface {
-	SQL() string
-} {
+func (b *UpdateBuilder) Returning(columns ...string) Statement {
 	b.returning = columns
 	return b
 }
 
+// Clone returns a deep copy of the update, including its where chain.
+func (b *UpdateBuilder) Clone() UpdateWhereQuery {
+	return b.clone()
+}
+
+func (b *UpdateBuilder) clone() *UpdateBuilder {
+	c := &UpdateBuilder{
+		table:       b.table,
+		columns:     slices.Clone(b.columns),
+		returning:   slices.Clone(b.returning),
+		values:      slices.Clone(b.values),
+		exprs:       maps.Clone(b.exprs),
+		key:         b.key.Clone(),
+		withDeleted: b.withDeleted,
+		err:         b.err,
+		tags:        maps.Clone(b.tags),
+	}
+	if b.WhereBuilder != nil {
+		c.WhereBuilder = &WhereBuilder[UpdateReturningQuery]{
+			parent: c,
+			where:  b.where.Clone(),
+		}
+	}
+	return c
+}
+
+func (b *UpdateBuilder) cloneWhere() *WhereBuilder[UpdateReturningQuery] {
+	return b.clone().WhereBuilder
+}
+
 func (b *UpdateBuilder) SQL() string {
 	return SQL(b)
 }
 
-func (b *UpdateBuilder) GetTable() string {
-	return b.table
+func (b *UpdateBuilder) Pretty() string {
+	return Pretty(b)
+}
+
+func (b *UpdateBuilder) Interpolate(opts ...RenderOption) string {
+	return Interpolate(b, opts...)
+}
+
+func (b *UpdateBuilder) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(b, opts...)
+}
+
+func (b *UpdateBuilder) Tag(key, value string) Statement {
+	b.tags.set(key, value)
+	return b
+}
+
+func (b *UpdateBuilder) GetTags() Tags {
+	return b.tags
+}
+
+func (b *UpdateBuilder) Build(opts ...RenderOption) (string, error) {
+	return Build(b, opts...)
 }
 
+func (b *UpdateBuilder) Err() error {
+	return Err(b)
+}
+
+func (b *UpdateBuilder) Args() []any {
+	return Args(b)
+}
+
+func (b *UpdateBuilder) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(b, opts...)
+}
+
+// GetPosition implements queryHelper
 func (b *UpdateBuilder) GetPosition() *int {
-	return &b.pos
+	return new(int)
+}
+
+func (b *UpdateBuilder) GetTable() string {
+	return b.table
 }
 
 func (b *UpdateBuilder) GetWhere() *WhereCondition {
-	return b.where
+	var where *WhereCondition
+	if b.WhereBuilder != nil {
+		where = b.where
+	}
+	if b.key != nil {
+		return and(b.key.Clone(), where)
+	}
+	return where
 }
 
 func (b *UpdateBuilder) GetColumns() []string {
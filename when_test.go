@@ -0,0 +1,100 @@
+package sqlbuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhen(t *testing.T) {
+	t.Run("case=builders", func(t *testing.T) {
+		for _, admin := range []bool{true, false} {
+			q := Select("id").From("users").
+				When(admin, func(q SelectFromQuery) {
+					q.InnerJoin("roles").On("roles.id", "users.role_id")
+				}).
+				Where("org_id", Equals).
+				When(!admin, func(w WhereOptions[SelectFromQuery]) {
+					w.And("public", IsTrue)
+				})
+			if admin {
+				require.Equal(t, "SELECT id FROM users INNER JOIN roles ON roles.id = users.role_id WHERE org_id = $1", q.SQL())
+			} else {
+				require.Equal(t, "SELECT id FROM users WHERE org_id = $1 AND public IS TRUE", q.SQL())
+			}
+		}
+
+		require.Equal(t, "INSERT INTO users (id) VALUES ($1) RETURNING id",
+			Insert("id").Into("users").When(true, func(q InsertIntoQuery) { q.Returning("id") }).SQL())
+		require.Equal(t, "UPDATE users SET name = $1",
+			Update("users").Set("name").When(false, func(q UpdateWhereQuery) { q.Where("id", Equals) }).SQL())
+		require.Equal(t, "DELETE FROM users WHERE id = $1",
+			Delete().From("users").When(true, func(q DeleteFromQuery) { q.Where("id", Equals) }).SQL())
+		require.Equal(t, "DROP TABLE IF EXISTS users",
+			DropTable("users").When(true, func(b *DropTableBuilder) { b.IfExists() }).SQL())
+		require.Equal(t, "CREATE INDEX i ON users (email)",
+			CreateIndex("i").On("users", "email").When(false, func(b *CreateIndexBuilder) { b.Unique() }).SQL())
+	})
+
+	t.Run("case=optional", func(t *testing.T) {
+		type search struct {
+			Name   string
+			Roles  []string
+			Age    *int
+			Active bool
+			Since  time.Time
+		}
+		build := func(in search) SelectFromQuery {
+			return Select().From("users").
+				WhereOptional("name", Like, in.Name).
+				AndOptional("role", In(0), in.Roles).
+				AndOptional("age", GreaterThan, in.Age).
+				OrOptional("active", IsTrue, in.Active).
+				AndOptional("created_at", GreaterThanOrEqual, in.Since).
+				Parent()
+		}
+
+		q := build(search{})
+		require.Equal(t, "SELECT * FROM users", q.SQL())
+		require.Empty(t, q.Args())
+
+		age := 0
+		q = build(search{Roles: []string{"a", "b"}, Age: &age, Active: true})
+		require.Equal(t, "SELECT * FROM users WHERE role IN ($1, $2) AND age > $3 OR active IS TRUE", q.SQL())
+		require.Equal(t, []any{"a", "b", &age}, q.Args())
+
+		q = build(search{Name: "a%", Since: time.Unix(0, 0)})
+		require.Equal(t, "SELECT * FROM users WHERE name LIKE $1 AND created_at >= $2", q.SQL())
+		require.Equal(t, []any{"a%", time.Unix(0, 0)}, q.Args())
+
+		require.Equal(t, "DELETE FROM users WHERE id = $1",
+			Delete().From("users").WhereOptional("org_id", Equals, nil).And("id", Equals).SQL())
+		require.Equal(t, "UPDATE users SET name = $1 WHERE id = $2 AND org_id = $3",
+			Update("users").Set("name").WhereOptional("id", Equals, 1).AndOptional("org_id", Equals, 2).AndOptional("x", Equals, "").SQL())
+	})
+
+	t.Run("case=optional after unbound placeholders", func(t *testing.T) {
+		q := Select().From("users").Where("org_id", Equals).AndOptional("name", Equals, "bob").And("x", Equals).Parent()
+		require.Equal(t, "SELECT * FROM users WHERE org_id = $1 AND name = $2 AND x = $3", q.SQL())
+		require.Equal(t, []any{nil, "bob"}, q.Args())
+
+		var args []any
+		_, err := q.Build(WithArgs(7, 8), CaptureArgs(&args))
+		require.NoError(t, err)
+		require.Equal(t, []any{7, "bob", 8}, args)
+
+		// Too few values would shift the bound ones to the wrong placeholders.
+		_, err = q.Build(CaptureArgs(&args))
+		require.ErrorIs(t, err, ErrMissingValue)
+
+		// Placeholders after the last bound value are left to the caller.
+		_, err = q.Build(WithArgs(7), CaptureArgs(&args))
+		require.NoError(t, err)
+		require.Equal(t, []any{7, "bob"}, args)
+
+		u := Update("users").Set("name").WhereOptional("id", Equals, 1)
+		require.Equal(t, "UPDATE users SET name = $1 WHERE id = $2", u.SQL())
+		require.Equal(t, []any{nil, 1}, u.Args())
+	})
+}
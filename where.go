@@ -1,23 +1,44 @@
 package sqlbuilder
 
+import (
+	"reflect"
+	"slices"
+)
+
 type (
 	WhereOptions[T any] interface {
 		And(column string, operator Operator) WhereOptions[T]
 		Or(column string, operator Operator) WhereOptions[T]
+		// AndOptional and OrOptional add a condition binding value, or
+		// nothing when value is nil, zero or empty, see WhereOptional.
+		AndOptional(column string, operator Operator, value any) WhereOptions[T]
+		OrOptional(column string, operator Operator, value any) WhereOptions[T]
+		// When calls f with the where chain when cond is true.
+		When(cond bool, f func(w WhereOptions[T])) WhereOptions[T]
 		Order[T]
 		Parent() T
-		SQL() string
+		Clone() WhereOptions[T]
+		Statement
 	}
 	Where[T any] interface {
 		Where(column string, operator Operator) WhereOptions[T]
+		// WhereOptional starts the where chain with a condition binding
+		// value, or with no condition when value is nil, the zero value of
+		// its type or an empty slice or map. The values of In and NotIn
+		// are taken from a slice, whatever their count, and a unary
+		// operator such as IsNull is added when value is set.
+		WhereOptional(column string, operator Operator, value any) WhereOptions[T]
 	}
 	LogicalOperator string
 	WhereCondition  struct {
 		ColumnA string
 		Op      Operator
 		ColumnB string
+		values  []any
 		nextOp  LogicalOperator
 		next    *WhereCondition
+		// group is rendered in parentheses in place of the condition.
+		group *WhereCondition
 	}
 )
 
@@ -44,16 +65,54 @@ func (w *WhereBuilder[T]) Where(column string, operator Operator) WhereOptions[T
 	return w
 }
 
+func (w *WhereBuilder[T]) WhereOptional(column string, operator Operator, value any) WhereOptions[T] {
+	w.where = optional(column, operator, value)
+	return w
+}
+
 func (w *WhereBuilder[T]) And(column string, operator Operator) WhereOptions[T] {
-	WhereOperator(w.where, operator, column, And)
+	w.add(And, &WhereCondition{ColumnA: column, Op: operator})
 	return w
 }
 
 func (w *WhereBuilder[T]) Or(column string, operator Operator) WhereOptions[T] {
-	WhereOperator(w.where, operator, column, Or)
+	w.add(Or, &WhereCondition{ColumnA: column, Op: operator})
+	return w
+}
+
+func (w *WhereBuilder[T]) AndOptional(column string, operator Operator, value any) WhereOptions[T] {
+	w.add(And, optional(column, operator, value))
+	return w
+}
+
+func (w *WhereBuilder[T]) OrOptional(column string, operator Operator, value any) WhereOptions[T] {
+	w.add(Or, optional(column, operator, value))
 	return w
 }
 
+func (w *WhereBuilder[T]) When(cond bool, f func(w WhereOptions[T])) WhereOptions[T] {
+	if cond {
+		f(w)
+	}
+	return w
+}
+
+// add appends c to the chain with lo, or starts the chain with c when the
+// optional condition starting it was left out.
+func (w *WhereBuilder[T]) add(lo LogicalOperator, c *WhereCondition) {
+	switch {
+	case c == nil:
+	case w.where == nil:
+		w.where = c
+	default:
+		last := w.where
+		for last.next != nil {
+			last = last.next
+		}
+		last.nextOp, last.next = lo, c
+	}
+}
+
 func (w *WhereBuilder[T]) Parent() T {
 	return w.parent
 }
@@ -66,6 +125,184 @@ func (w *WhereBuilder[T]) OrderBy(orderBy OrderBy, columns ...string) T {
 	return w.parent
 }
 
+// Clone deep copies the statement the where chain belongs to and returns the
+// where chain of the copy, so further conditions only apply to the copy.
+func (w *WhereBuilder[T]) Clone() WhereOptions[T] {
+	switch p := any(w.parent).(type) {
+	case interface{ cloneWhere() *WhereBuilder[T] }:
+		return p.cloneWhere()
+	}
+	return &WhereBuilder[T]{
+		parent: w.parent,
+		where:  w.where.Clone(),
+	}
+}
+
 func (w *WhereBuilder[T]) SQL() string {
 	return SQL(w.parent)
 }
+
+func (w *WhereBuilder[T]) Pretty() string {
+	return Pretty(w.parent)
+}
+
+func (w *WhereBuilder[T]) Interpolate(opts ...RenderOption) string {
+	return Interpolate(w.parent, opts...)
+}
+
+func (w *WhereBuilder[T]) Fingerprint(opts ...RenderOption) Fingerprint {
+	return FingerprintOf(w.parent, opts...)
+}
+
+func (w *WhereBuilder[T]) Tag(key, value string) Statement {
+	any(w.parent).(Statement).Tag(key, value)
+	return w
+}
+
+func (w *WhereBuilder[T]) Build(opts ...RenderOption) (string, error) {
+	return Build(w.parent, opts...)
+}
+
+func (w *WhereBuilder[T]) Err() error {
+	return Err(w.parent)
+}
+
+func (w *WhereBuilder[T]) Args() []any {
+	return Args(w.parent)
+}
+
+func (w *WhereBuilder[T]) Compile(opts ...RenderOption) (*Template, error) {
+	return Compile(w.parent, opts...)
+}
+
+// Cond returns a condition comparing column with the bound values, to be
+// combined with AllOf and AnyOf and added to a select with Filter.
+func Cond(column string, operator Operator, values ...any) *WhereCondition {
+	return &WhereCondition{ColumnA: column, Op: operator, values: values}
+}
+
+// AllOf returns a condition which holds when all of conds hold.
+func AllOf(conds ...*WhereCondition) *WhereCondition {
+	return combine(And, conds)
+}
+
+// AnyOf returns a condition which holds when any of conds holds.
+func AnyOf(conds ...*WhereCondition) *WhereCondition {
+	return combine(Or, conds)
+}
+
+// combine chains copies of conds with op and puts the chain in parentheses.
+func combine(op LogicalOperator, conds []*WhereCondition) *WhereCondition {
+	var first, last *WhereCondition
+	for _, c := range conds {
+		if c = c.Clone(); c == nil {
+			continue
+		}
+		if c.next != nil {
+			c = &WhereCondition{group: c}
+		}
+		if first == nil {
+			first = c
+		} else {
+			last.nextOp, last.next = op, c
+		}
+		last = c
+	}
+	if first == nil || first.next == nil {
+		return first
+	}
+	return &WhereCondition{group: first}
+}
+
+// and returns the chain c followed by AND and the chain w. Either chain is
+// put in parentheses when it contains OR, so it can't absorb the other. The
+// last condition of c is changed.
+func and(c, w *WhereCondition) *WhereCondition {
+	if c == nil {
+		return w
+	}
+	if c.group != nil && c.next == nil && !c.group.hasOr() {
+		c = c.group
+	}
+	if c.hasOr() {
+		c = &WhereCondition{group: c}
+	}
+	if w == nil {
+		return c
+	}
+	if w.hasOr() {
+		w = &WhereCondition{group: w}
+	}
+	last := c
+	for last.next != nil {
+		last = last.next
+	}
+	last.nextOp, last.next = And, w
+	return c
+}
+
+// hasOr reports whether the chain joins any of its conditions with OR.
+func (c *WhereCondition) hasOr() bool {
+	for ; c != nil; c = c.next {
+		if c.nextOp == Or {
+			return true
+		}
+	}
+	return false
+}
+
+// optional returns the condition of WhereOptional, or nil when value is
+// not set.
+func optional(column string, operator Operator, value any) *WhereCondition {
+	v := reflect.ValueOf(value)
+	switch {
+	case value == nil, v.IsZero():
+		return nil
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+		if v.Len() == 0 {
+			return nil
+		}
+	}
+
+	switch op := operator.get().(type) {
+	case BasicOperator:
+		if op.unary() {
+			return &WhereCondition{ColumnA: column, Op: op}
+		}
+	case SpecialOperator:
+		values := []any{value}
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			values = make([]any, v.Len())
+			for i := range values {
+				values[i] = v.Index(i).Interface()
+			}
+		}
+		_, name := op()
+		return Cond(column, SpecialOperator(func() (int, string) { return len(values), name }), values...)
+	}
+	return Cond(column, operator, value)
+}
+
+// Clone deep copies the condition and every condition chained after it.
+func (c *WhereCondition) Clone() *WhereCondition {
+	if c == nil {
+		return nil
+	}
+	n := *c
+	n.values = slices.Clone(c.values)
+	n.next = c.next.Clone()
+	n.group = c.group.Clone()
+	return &n
+}
+
+// walk calls f for every condition of the chain, including the conditions
+// of groups.
+func (c *WhereCondition) walk(f func(c *WhereCondition)) {
+	for ; c != nil; c = c.next {
+		if c.group != nil {
+			c.group.walk(f)
+			continue
+		}
+		f(c)
+	}
+}